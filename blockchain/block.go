@@ -18,6 +18,9 @@ type Block struct {
 	Signature string `json:"signature"`
 	Payload   []byte `json:"payload"`
 
+	// payload is dropped by light node
+	Pruned bool `json:"-"`
+
 	recoveredChainID string
 	data             []byte
 }
@@ -39,9 +42,14 @@ func (b Block) DataForHashing() []byte {
 	return append([]byte(data), b.Payload...)
 }
 
+// Hash of the block content, which should be the same as Hash
+func (b Block) ComputeHash() string {
+	return base58.Encode(utils.SHA256(b.DataForHashing()))
+}
+
 // TODO: store result to reduce calculations
 func (b *Block) Validate() BlockValidationError {
-	if base58.Encode(utils.SHA256(b.DataForHashing())) != b.Hash {
+	if b.ComputeHash() != b.Hash {
 		return &InvalidBlockError{b, "hash value not match"}
 	} else if len(b.ChainID()) == 0 {
 		return &InvalidBlockError{b, "cannot recover chain id"}
@@ -1,9 +1,11 @@
 package blockchain
 
 import (
+	"context"
 	"github.com/Infnote/infnotechain/blockchain/crypto"
 	"github.com/Infnote/infnotechain/utils"
 	"github.com/mr-tron/base58"
+	"sync"
 	"time"
 )
 
@@ -13,18 +15,49 @@ type Chain struct {
 	key   *crypto.Key
 	Ref   int64
 	cache map[uint64]*Block
+
+	recovering bool
 }
 
 var loadedChains = map[string]*Chain{}
-var BlockSavedHook func(block *Block) = nil
+var blockSavedHooks []func(block *Block)
+var blockSavedHooksLock sync.RWMutex
+
+// Commit cached blocks of loaded chains before exiting
+func FlushChains() {
+	for _, chain := range loadedChains {
+		chain.CommitCache()
+	}
+}
 
 func ResetChainCache() {
 	loadedChains = map[string]*Chain{}
 }
 
+// Hooks are called in order of heights for every block saved,
+// no matter it is saved directly or committed from cache
+func AddBlockSavedHook(hook func(block *Block)) {
+	blockSavedHooksLock.Lock()
+	defer blockSavedHooksLock.Unlock()
+	blockSavedHooks = append(blockSavedHooks, hook)
+}
+
+func blockSaved(block *Block) {
+	blockSavedHooksLock.RLock()
+	hooks := blockSavedHooks
+	blockSavedHooksLock.RUnlock()
+	for _, hook := range hooks {
+		hook(block)
+	}
+}
+
 // Create a chain object with genesis block payload
 func CreateChain(payload []byte) *Chain {
-	key := crypto.NewKey()
+	return CreateChainWithKey(crypto.NewKey(), payload)
+}
+
+// Chain ID is the address of the key, such as a key of mnemonic
+func CreateChainWithKey(key *crypto.Key, payload []byte) *Chain {
 	chain := &Chain{
 		ID:    key.ToAddress(),
 		key:   key,
@@ -43,6 +76,45 @@ func NewOwnedChain(wif string) *Chain {
 	return &Chain{ID: key.ToAddress(), key: key, cache: map[uint64]*Block{}}
 }
 
+// Owned chain of a recovered key, which is recovering until it is synced
+// to the height of peers, blocks should not be created before that
+// or the chain forks. The state is saved before the key, so a chain
+// is never owned without it.
+func RecoverChain(key *crypto.Key) (*Chain, error) {
+	id := key.ToAddress()
+	if err := SharedStorage().SetRecovering(id, true); err != nil {
+		return nil, err
+	}
+
+	chain := LoadChain(id)
+	if chain == nil {
+		chain = &Chain{ID: id, key: key, cache: map[uint64]*Block{}, recovering: true}
+		chain.Sync()
+		return chain, nil
+	}
+
+	chain.key = key
+	chain.recovering = true
+	if err := SharedStorage().SaveChainKey(chain); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// Whether the owned chain is recovered and not synced yet
+func (c Chain) IsRecovering() bool {
+	return c.recovering
+}
+
+// Called once the chain is synced to the height of peers
+func (c *Chain) FinishRecovery() error {
+	if err := SharedStorage().SetRecovering(c.ID, false); err != nil {
+		return err
+	}
+	c.recovering = false
+	return nil
+}
+
 func NewReadonlyChain(id string) *Chain {
 	return &Chain{ID: id, cache: map[uint64]*Block{}}
 }
@@ -60,6 +132,7 @@ func LoadChain(id string) *Chain {
 	}
 	if len(wif) > 0 {
 		chain.key, _ = crypto.FromWIF(wif)
+		chain.recovering = SharedStorage().IsRecovering(id)
 	}
 
 	loadedChains[chain.ID] = chain
@@ -79,6 +152,22 @@ func LoadAllChains() []*Chain {
 	return chains
 }
 
+// Look up a block by hash in all saved chains
+func FindBlock(ctx context.Context, hash string) (*Chain, *Block) {
+	chainID, block := SharedStorage().FindBlock(hash)
+	if block == nil {
+		return nil, nil
+	}
+	chain := LoadChain(chainID)
+	if chain == nil {
+		return nil, nil
+	}
+	if block.Pruned {
+		block = chain.fetch(ctx, block)
+	}
+	return chain, block
+}
+
 func (c Chain) IsOwner() bool {
 	return c.key != nil
 }
@@ -90,15 +179,64 @@ func (c Chain) WIF() string {
 	return ""
 }
 
+// Pruned payload of light node will be fetched from other peers,
+// blocks failed to fetch are returned pruned
 func (c Chain) GetBlock(height uint64) *Block {
+	return c.GetBlockContext(context.Background(), height)
+}
+
+func (c Chain) GetBlocks(from uint64, to uint64) []*Block {
+	return c.GetBlocksContext(context.Background(), from, to)
+}
+
+// Payloads are fetched until the context is done
+func (c Chain) GetBlockContext(ctx context.Context, height uint64) *Block {
+	block := c.GetLocalBlock(height)
+	if block != nil && block.Pruned {
+		return c.fetch(ctx, block)
+	}
+	return block
+}
+
+func (c Chain) GetBlocksContext(ctx context.Context, from uint64, to uint64) []*Block {
+	blocks := SharedStorage().GetBlocks(c.Ref, from, to)
+	for i, block := range blocks {
+		if block.Pruned {
+			blocks[i] = c.fetch(ctx, block)
+		}
+	}
+	return blocks
+}
+
+// Block saved in this node, payload may be pruned
+func (c Chain) GetLocalBlock(height uint64) *Block {
 	if block := c.cache[height]; block != nil {
 		return block
 	}
 	return SharedStorage().GetBlock(c.Ref, height)
 }
 
-func (c Chain) GetBlocks(from uint64, to uint64) []*Block {
-	return SharedStorage().GetBlocks(c.Ref, from, to)
+// Blocks with payload saved in this node, stops before the first pruned block
+func (c Chain) GetLocalBlocks(from uint64, to uint64) []*Block {
+	var blocks []*Block
+	for _, block := range SharedStorage().GetBlocks(c.Ref, from, to) {
+		if block.Pruned {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// Returns the pruned block itself if failed to fetch
+func (c Chain) fetch(ctx context.Context, block *Block) *Block {
+	if PayloadFetcher == nil || ctx.Err() != nil {
+		return block
+	}
+	if fetched := PayloadFetcher(ctx, c.ID, block.Height); fetched != nil {
+		return fetched
+	}
+	return block
 }
 
 func (c Chain) CreateBlock(payload []byte) *Block {
@@ -108,7 +246,7 @@ func (c Chain) CreateBlock(payload []byte) *Block {
 
 	block := &Block{Height: c.Count, Time: uint64(time.Now().Unix()), Payload: payload}
 	if c.Count > 0 {
-		prev := c.GetBlock(c.Count - 1)
+		prev := c.GetLocalBlock(c.Count - 1)
 		if prev == nil {
 			utils.L.Fatalf("attempt get block at height [%v] on chain [%v] failed", c.Count - 1, c.ID)
 		}
@@ -132,7 +270,7 @@ func (c Chain) ValidateBlock(block *Block) BlockValidationError {
 		return MismatchedIDError{c.ID, block.ChainID()}
 	}
 
-	b := c.GetBlock(block.Height)
+	b := c.GetLocalBlock(block.Height)
 
 	if b != nil {
 		if b.Hash != block.Hash || b.PrevHash != block.PrevHash {
@@ -142,7 +280,7 @@ func (c Chain) ValidateBlock(block *Block) BlockValidationError {
 	}
 
 	if block.Height > 0 {
-		prev := c.GetBlock(block.Height-1)
+		prev := c.GetLocalBlock(block.Height-1)
 		if prev == nil {
 			return DangledBlockError{b, "previous block is not exist"}
 		}
@@ -156,12 +294,12 @@ func (c Chain) ValidateBlock(block *Block) BlockValidationError {
 
 func (c *Chain) SaveBlock(block *Block) bool {
 	if c.ValidateBlock(block) == nil {
+		retained := c.RetainedFrom()
 		SharedStorage().SaveBlock(c.Ref, block)
 		SharedStorage().IncreaseCount(c)
-		utils.L.Debugf("new block saved: %#v", block.Hash)
-		if BlockSavedHook != nil {
-			BlockSavedHook(block)
-		}
+		c.pruneLeft(retained)
+		utils.L.With(utils.Fields{"chain_id": c.ID, "height": block.Height}).Debugf("new block saved: %#v", block.Hash)
+		blockSaved(block)
 		return true
 	}
 	return false
@@ -172,18 +310,26 @@ func (c *Chain) CacheBlock(block *Block) BlockValidationError {
 	if err != nil {
 		return err
 	}
-	utils.L.Debugf("block cached: \n%v", block)
+	utils.L.With(utils.Fields{"chain_id": c.ID, "height": block.Height}).Debugf("block cached: \n%v", block)
 	c.cache[block.Height] = block
 	return nil
 }
 
 func (c *Chain) CommitCache() {
-	for _, block := range c.cache {
+	retained := c.RetainedFrom()
+	for {
+		block := c.cache[c.Count]
+		if block == nil {
+			break
+		}
 		SharedStorage().SaveBlock(c.Ref, block)
 		SharedStorage().IncreaseCount(c)
 		delete(c.cache, block.Height)
-		utils.L.Debugf("block saved: \n%v", block)
+		utils.L.With(utils.Fields{"chain_id": c.ID, "height": block.Height}).Debugf("block saved: \n%v", block)
+		blockSaved(block)
 	}
+	c.cache = map[uint64]*Block{}
+	c.pruneLeft(retained)
 }
 
 func (c *Chain) Sync() {
@@ -31,6 +31,10 @@ func FromWIF(WIF string) (*Key, error) {
 	if err != nil {
 		return nil, err
 	}
+	// only WIF of compressed public key is supported
+	if len(data) != 38 {
+		return nil, fmt.Errorf("WIF length is not matched")
+	}
 
 	hashValue := utils.SHA256(utils.SHA256(data[:len(data)-4]))
 	checksum := data[len(data)-4:]
@@ -80,6 +84,9 @@ func (k Key) Sign(msg []byte) []byte {
 // secp256k1 standard signature format put recid back of signature
 // but bitcoin put it front and add 27 or 31 for uncompressed or compressed public key
 func RecoverAddress(sig []byte, msg []byte) (string, error) {
+	if len(sig) != 65 {
+		return "", fmt.Errorf("signature length is not matched")
+	}
 	pub, err := crypto.SigToPub(utils.SHA256(msg), append(sig[1:], sig[0]-31))
 	if err != nil {
 		return "", err
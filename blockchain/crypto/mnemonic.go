@@ -0,0 +1,37 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"fmt"
+	"github.com/tyler-smith/go-bip39"
+	"strings"
+)
+
+// 256 bits of entropy, encoded as 24 words
+const mnemonicEntropyBits = 256
+
+// New BIP39 mnemonic for FromMnemonic, words should be written down
+// as the backup of the key
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// Key of a BIP39 mnemonic and an optional passphrase, which is the master
+// key of BIP32 derived from the seed, the same key is always returned
+// for the same words and passphrase
+func FromMnemonic(mnemonic string, passphrase string) (*Key, error) {
+	mnemonic = strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("mnemonic is invalid, check the words and their order")
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	return FromBytes(mac.Sum(nil)[:32])
+}
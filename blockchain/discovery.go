@@ -0,0 +1,48 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// A chain learned from peers but not subscribed,
+// genesis payload is the metadata of the chain
+type DiscoveredChain struct {
+	ID      string
+	Genesis *Block
+	Count   uint64
+	Last    time.Time
+}
+
+// Nil without error if the chain is not discovered
+func GetDiscoveredChain(id string) (*DiscoveredChain, error) {
+	return SharedStorage().GetDiscoveredChain(id)
+}
+
+// Discovered chains which are not subscribed yet
+func GetAvailableChains() ([]*DiscoveredChain, error) {
+	discovered, err := SharedStorage().GetDiscoveredChains()
+	if err != nil {
+		return nil, err
+	}
+	var chains []*DiscoveredChain
+	for _, chain := range discovered {
+		if LoadChain(chain.ID) == nil {
+			chains = append(chains, chain)
+		}
+	}
+	return chains, nil
+}
+
+func (c *DiscoveredChain) Save() error {
+	return SharedStorage().SaveDiscoveredChain(c)
+}
+
+// Metadata from genesis payload created by 'createchain'
+func (c DiscoveredChain) Metadata() map[string]string {
+	metadata := map[string]string{}
+	if c.Genesis != nil {
+		_ = json.Unmarshal(c.Genesis.Payload, &metadata)
+	}
+	return metadata
+}
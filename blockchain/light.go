@@ -0,0 +1,116 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/utils"
+	"strconv"
+	"strings"
+)
+
+// Light nodes validate and keep every block, but only keep payloads of
+// the recent 'light.window' blocks and heights in 'light.ranges' of a chain.
+// Genesis is always kept as it is the metadata of the chain.
+// Fetching gives up when the context is done.
+var PayloadFetcher func(ctx context.Context, chainID string, height uint64) *Block = nil
+
+type heightRange struct {
+	From uint64
+	To   uint64
+}
+
+func IsLightNode() bool {
+	return utils.GetBool("light.enabled")
+}
+
+func lightWindow() uint64 {
+	window := utils.GetInt("light.window")
+	if window < 0 {
+		return 0
+	}
+	return uint64(window)
+}
+
+// Ranges are configured as "[chain id]:[from]-[to]"
+func lightRanges(chainID string) []heightRange {
+	var ranges []heightRange
+	for _, v := range utils.GetStringSlice("light.ranges") {
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 || parts[0] != chainID {
+			continue
+		}
+		heights := strings.Split(parts[1], "-")
+		if len(heights) != 2 {
+			utils.L.Warningf("invalid light range: %v", v)
+			continue
+		}
+		from, err1 := strconv.ParseUint(heights[0], 10, 64)
+		to, err2 := strconv.ParseUint(heights[1], 10, 64)
+		if err1 != nil || err2 != nil || from > to {
+			utils.L.Warningf("invalid light range: %v", v)
+			continue
+		}
+		ranges = append(ranges, heightRange{from, to})
+	}
+	return ranges
+}
+
+// The lowest height of recent blocks with payloads
+func (c Chain) RetainedFrom() uint64 {
+	if !IsLightNode() || c.Count <= lightWindow() {
+		return 0
+	}
+	return c.Count - lightWindow()
+}
+
+// Whether payload of the block at height is kept
+func (c Chain) Retains(height uint64) bool {
+	if height == 0 || height >= c.RetainedFrom() {
+		return true
+	}
+	for _, r := range lightRanges(c.ID) {
+		if r.From <= height && height <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// Drop payloads not retained between two heights
+func (c Chain) prune(from uint64, to uint64) {
+	if !IsLightNode() || from > to {
+		return
+	}
+
+	start := from
+	for h := from; h <= to+1; h++ {
+		if h <= to && !c.Retains(h) {
+			continue
+		}
+		if start < h {
+			SharedStorage().PrunePayloads(c.Ref, start, h-1)
+		}
+		start = h + 1
+	}
+}
+
+// Drop payloads of blocks left the window since it started from the height
+func (c Chain) pruneLeft(retained uint64) {
+	if retained < 1 {
+		retained = 1
+	}
+	if from := c.RetainedFrom(); from > retained {
+		c.prune(retained, from-1)
+	}
+}
+
+// Drop payloads of all chains left the window, used when node starts
+func PruneChains() {
+	if !IsLightNode() {
+		return
+	}
+	for _, chain := range LoadAllChains() {
+		if from := chain.RetainedFrom(); from > 1 {
+			chain.prune(1, from-1)
+		}
+	}
+}
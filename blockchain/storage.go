@@ -5,11 +5,19 @@ type Storage interface {
 	GetAllChains(func(ref int64, id string, wif string, count uint64))
 	GetBlock(id int64, height uint64) *Block
 	GetBlockByHash(id int64, hash string) *Block
+	FindBlock(hash string) (chainID string, block *Block)
 	GetBlocks(id int64, from uint64, to uint64) []*Block
 	SaveChain(chain *Chain) error
+	SaveChainKey(chain *Chain) error
+	IsRecovering(chainID string) bool
+	SetRecovering(chainID string, recovering bool) error
 	IncreaseCount(chain *Chain)
 	SaveBlock(id int64, block *Block)
 	CleanChain(chain *Chain)
+	PrunePayloads(id int64, from uint64, to uint64)
+	GetDiscoveredChain(id string) (*DiscoveredChain, error)
+	GetDiscoveredChains() ([]*DiscoveredChain, error)
+	SaveDiscoveredChain(chain *DiscoveredChain) error
 }
 
 var instance Storage
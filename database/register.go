@@ -5,19 +5,32 @@ import (
 	"github.com/Infnote/infnotechain/blockchain"
 	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/utils"
+	"github.com/Infnote/infnotechain/webhook"
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/spf13/viper"
 )
 
+var shared *SQLiteDriver
+
 func Register() {
-	db, err := sql.Open("sqlite3", viper.GetString("data.file"))
+	db, err := sql.Open("sqlite3", utils.GetString("data.file"))
 	if err != nil {
 		utils.L.Fatal(err)
 	}
 
 	s := &SQLiteDriver{db}
+	shared = s
 	blockchain.RegisterStorage(s)
 	network.RegisterStorage(s)
+	webhook.RegisterStorage(s)
+}
+
+func Close() {
+	if shared == nil {
+		return
+	}
+	if err := shared.db.Close(); err != nil {
+		utils.L.Warningf("failed to close database: %v", err)
+	}
 }
 
 func Migrate() {
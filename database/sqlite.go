@@ -3,11 +3,12 @@ package database
 import (
 	"database/sql"
 	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
 	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/utils"
+	"github.com/Infnote/infnotechain/webhook"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/mr-tron/base58"
-	"github.com/spf13/viper"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -19,6 +20,8 @@ type SQLiteDriver struct {
 }
 
 func (s SQLiteDriver) GetChain(chainID string, ref *int64, wif *string, count *uint64) bool {
+	defer metrics.StorageLatency.Since("get_chain", time.Now())
+
 	query := `SELECT id, wif, count FROM chains WHERE chain_id = ? LIMIT 1`
 	rows, err := s.db.Query(query, chainID)
 	if err != nil {
@@ -61,6 +64,8 @@ func (s SQLiteDriver) GetAllChains(yield func(ref int64, id string, wif string,
 
 // TODO: returning an instance of Block may not be an good practice
 func (s SQLiteDriver) GetBlock(id int64, height uint64) *blockchain.Block {
+	defer metrics.StorageLatency.Since("get_block", time.Now())
+
 	query := `SELECT height, time, hash, prev_hash, signature, payload FROM blocks 
 			  WHERE height = ? AND chain_id = ? LIMIT 1`
 	rows, err := s.db.Query(query, height, id)
@@ -78,7 +83,9 @@ func (s SQLiteDriver) GetBlock(id int64, height uint64) *blockchain.Block {
 		}
 
 		if payload == "*" {
-			block.Payload, err = ioutil.ReadFile(viper.GetString("data.root") + block.Hash)
+			block.Payload, err = ioutil.ReadFile(utils.GetString("data.root") + block.Hash)
+		} else if payload == "-" {
+			block.Pruned = true
 		} else {
 			block.Payload, err = base58.Decode(payload)
 		}
@@ -92,6 +99,8 @@ func (s SQLiteDriver) GetBlock(id int64, height uint64) *blockchain.Block {
 }
 
 func (s SQLiteDriver) GetBlockByHash(id int64, hash string) *blockchain.Block {
+	defer metrics.StorageLatency.Since("get_block_by_hash", time.Now())
+
 	query := `SELECT height, time, hash, prev_hash, signature, payload FROM blocks 
 			  WHERE hash = ? AND chain_id = ? LIMIT 1`
 	rows, err := s.db.Query(query, hash, id)
@@ -109,7 +118,9 @@ func (s SQLiteDriver) GetBlockByHash(id int64, hash string) *blockchain.Block {
 		}
 
 		if payload == "*" {
-			block.Payload, err = ioutil.ReadFile(viper.GetString("data.root") + block.Hash)
+			block.Payload, err = ioutil.ReadFile(utils.GetString("data.root") + block.Hash)
+		} else if payload == "-" {
+			block.Pruned = true
 		} else {
 			block.Payload, err = base58.Decode(payload)
 		}
@@ -122,9 +133,48 @@ func (s SQLiteDriver) GetBlockByHash(id int64, hash string) *blockchain.Block {
 	return nil
 }
 
+// Look up a block by hash in all chains
+func (s SQLiteDriver) FindBlock(hash string) (string, *blockchain.Block) {
+	defer metrics.StorageLatency.Since("find_block", time.Now())
+
+	query := `SELECT c.chain_id, b.height, b.time, b.hash, b.prev_hash, b.signature, b.payload 
+			  FROM blocks b JOIN chains c ON b.chain_id = c.id WHERE b.hash = ? LIMIT 1`
+	rows, err := s.db.Query(query, hash)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		block := &blockchain.Block{}
+		var chainID string
+		var payload string
+		err = rows.Scan(&chainID, &block.Height, &block.Time, &block.Hash, &block.PrevHash, &block.Signature, &payload)
+		if err != nil {
+			utils.L.Fatal(err)
+		}
+
+		if payload == "*" {
+			block.Payload, err = ioutil.ReadFile(utils.GetString("data.root") + block.Hash)
+		} else if payload == "-" {
+			block.Pruned = true
+		} else {
+			block.Payload, err = base58.Decode(payload)
+		}
+
+		if err != nil {
+			utils.L.Fatal(err)
+		}
+		return chainID, block
+	}
+	return "", nil
+}
+
 // Get blocks of specific internal id by two heights
 // 'from' and 'to' are both included
 func (s SQLiteDriver) GetBlocks(id int64, from uint64, to uint64) []*blockchain.Block {
+	defer metrics.StorageLatency.Since("get_blocks", time.Now())
+
 	query := `SELECT height, time, hash, prev_hash, signature, payload FROM blocks 
 			  WHERE chain_id = ? AND height >= ? AND height <= ?`
 	rows, err := s.db.Query(query, id, from, to)
@@ -144,7 +194,9 @@ func (s SQLiteDriver) GetBlocks(id int64, from uint64, to uint64) []*blockchain.
 		}
 
 		if payload == "*" {
-			block.Payload, err = ioutil.ReadFile(viper.GetString("data.root") + block.Hash)
+			block.Payload, err = ioutil.ReadFile(utils.GetString("data.root") + block.Hash)
+		} else if payload == "-" {
+			block.Pruned = true
 		} else {
 			block.Payload, err = base58.Decode(payload)
 		}
@@ -158,6 +210,8 @@ func (s SQLiteDriver) GetBlocks(id int64, from uint64, to uint64) []*blockchain.
 }
 
 func (s SQLiteDriver) SaveChain(chain *blockchain.Chain) error {
+	defer metrics.StorageLatency.Since("save_chain", time.Now())
+
 	query := `
 		INSERT INTO chains (chain_id, wif)
 		VALUES (?, ?)
@@ -171,7 +225,38 @@ func (s SQLiteDriver) SaveChain(chain *blockchain.Chain) error {
 	return err
 }
 
+// Key of a read-only chain is saved when the chain is recovered
+func (s SQLiteDriver) SaveChainKey(chain *blockchain.Chain) error {
+	defer metrics.StorageLatency.Since("save_chain_key", time.Now())
+
+	query := `UPDATE chains SET wif = ? WHERE id = ?`
+	_, err := s.db.Exec(query, chain.WIF(), chain.Ref)
+	return err
+}
+
+func (s SQLiteDriver) IsRecovering(chainID string) bool {
+	query := `SELECT chain_id FROM recovering_chains WHERE chain_id = ?`
+	rows, err := s.db.Query(query, chainID)
+	if err != nil {
+		utils.L.Debugf("sqlite query error: %v", err)
+		return false
+	}
+	defer func() { _ = rows.Close() }()
+	return rows.Next()
+}
+
+func (s SQLiteDriver) SetRecovering(chainID string, recovering bool) error {
+	query := `DELETE FROM recovering_chains WHERE chain_id = ?`
+	if recovering {
+		query = `INSERT OR IGNORE INTO recovering_chains (chain_id) VALUES (?)`
+	}
+	_, err := s.db.Exec(query, chainID)
+	return err
+}
+
 func (s SQLiteDriver) IncreaseCount(chain *blockchain.Chain) {
+	defer metrics.StorageLatency.Since("increase_count", time.Now())
+
 	query := `UPDATE chains SET count=count+1 WHERE id = ?`
 	_, err := s.db.Exec(query, chain.Ref)
 	if err != nil {
@@ -181,9 +266,11 @@ func (s SQLiteDriver) IncreaseCount(chain *blockchain.Chain) {
 }
 
 func (s SQLiteDriver) SaveBlock(id int64, block *blockchain.Block) {
+	defer metrics.StorageLatency.Since("save_block", time.Now())
+
 	payload := "*"
 	if len(block.Payload) > 1024 * 100 {
-		if err := ioutil.WriteFile(viper.GetString("data.root") + block.Hash, block.Payload, 0655); err != nil {
+		if err := ioutil.WriteFile(utils.GetString("data.root") + block.Hash, block.Payload, 0655); err != nil {
 			utils.L.Fatal("failed to write payload to file, abort.")
 			return
 		}
@@ -212,6 +299,227 @@ func (s SQLiteDriver) SaveBlock(id int64, block *blockchain.Block) {
 	}
 }
 
+// Payloads are replaced with "-" and payload files are removed
+func (s SQLiteDriver) PrunePayloads(id int64, from uint64, to uint64) {
+	defer metrics.StorageLatency.Since("prune_payloads", time.Now())
+
+	query := `SELECT hash FROM blocks WHERE chain_id = ? AND height >= ? AND height <= ? AND payload = '*'`
+	rows, err := s.db.Query(query, id, from, to)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			utils.L.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+	_ = rows.Close()
+
+	query = `UPDATE blocks SET payload = '-' WHERE chain_id = ? AND height >= ? AND height <= ? AND payload != '-'`
+	if _, err := s.db.Exec(query, id, from, to); err != nil {
+		utils.L.Warningf("failed to prune payloads: %v", err)
+		return
+	}
+
+	for _, hash := range hashes {
+		if err := os.Remove(utils.GetString("data.root") + hash); err != nil {
+			utils.L.Warningf("%v", err)
+		}
+	}
+	utils.L.Debugf("payloads pruned from %v to %v", from, to)
+}
+
+func (s SQLiteDriver) scanDiscoveredChains(rows *sql.Rows) ([]*blockchain.DiscoveredChain, error) {
+	var chains []*blockchain.DiscoveredChain
+	for rows.Next() {
+		chain := &blockchain.DiscoveredChain{}
+		var genesis string
+		var last int64
+		err := rows.Scan(&chain.ID, &genesis, &chain.Count, &last)
+		if err != nil {
+			return nil, err
+		}
+		chain.Genesis, err = blockchain.DeserializeBlock([]byte(genesis))
+		if err != nil {
+			utils.L.Warningf("invalid genesis of discovered chain %v: %v", chain.ID, err)
+			continue
+		}
+		chain.Last = time.Unix(last, 0)
+		chains = append(chains, chain)
+	}
+	return chains, rows.Err()
+}
+
+func (s SQLiteDriver) GetDiscoveredChain(id string) (*blockchain.DiscoveredChain, error) {
+	query := `SELECT chain_id, genesis, count, last FROM discovered_chains WHERE chain_id = ?`
+	rows, err := s.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	chains, err := s.scanDiscoveredChains(rows)
+	if err != nil || len(chains) == 0 {
+		return nil, err
+	}
+	return chains[0], nil
+}
+
+func (s SQLiteDriver) GetDiscoveredChains() ([]*blockchain.DiscoveredChain, error) {
+	query := `SELECT chain_id, genesis, count, last FROM discovered_chains ORDER BY last DESC`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanDiscoveredChains(rows)
+}
+
+func (s SQLiteDriver) SaveDiscoveredChain(chain *blockchain.DiscoveredChain) error {
+	query := `INSERT INTO discovered_chains (chain_id, genesis, count, last) VALUES (?, ?, ?, ?)`
+
+	_, err := s.db.Exec(query, chain.ID, string(chain.Genesis.Serialize()), chain.Count, chain.Last.Unix())
+	if err != nil {
+		query = `UPDATE discovered_chains SET count=MAX(count, ?), last=? WHERE chain_id=?`
+		_, err = s.db.Exec(query, chain.Count, chain.Last.Unix(), chain.ID)
+	}
+	return err
+}
+
+func (s SQLiteDriver) scanDeliveries(rows *sql.Rows) []*webhook.Delivery {
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		delivery := &webhook.Delivery{}
+		var body string
+		var next int64
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.URL,
+			&delivery.ChainID,
+			&delivery.Height,
+			&body,
+			&delivery.Attempts,
+			&next,
+			&delivery.Error,
+			&delivery.Dead)
+		if err != nil {
+			utils.L.Fatal(err)
+		}
+		delivery.Body = []byte(body)
+		delivery.Next = time.Unix(next, 0)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (s SQLiteDriver) SaveDelivery(delivery *webhook.Delivery) {
+	defer metrics.StorageLatency.Since("save_delivery", time.Now())
+
+	query := `
+		INSERT INTO webhook_deliveries (url, chain_id, height, body, attempts, next, error, dead)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := s.db.Exec(
+		query,
+		delivery.URL,
+		delivery.ChainID,
+		delivery.Height,
+		string(delivery.Body),
+		delivery.Attempts,
+		delivery.Next.Unix(),
+		delivery.Error,
+		delivery.Dead)
+	if err != nil {
+		utils.L.Warningf("failed to queue webhook delivery: %v", err)
+		return
+	}
+	delivery.ID, _ = result.LastInsertId()
+}
+
+func (s SQLiteDriver) UpdateDelivery(delivery *webhook.Delivery) {
+	query := `UPDATE webhook_deliveries SET attempts=?, next=?, error=?, dead=? WHERE id=?`
+	_, err := s.db.Exec(query, delivery.Attempts, delivery.Next.Unix(), delivery.Error, delivery.Dead, delivery.ID)
+	if err != nil {
+		utils.L.Warningf("failed to update webhook delivery: %v", err)
+	}
+}
+
+func (s SQLiteDriver) DeleteDelivery(delivery *webhook.Delivery) {
+	query := `DELETE FROM webhook_deliveries WHERE id = ?`
+	if _, err := s.db.Exec(query, delivery.ID); err != nil {
+		utils.L.Warningf("failed to delete webhook delivery: %v", err)
+	}
+}
+
+func (s SQLiteDriver) GetDelivery(id int64) *webhook.Delivery {
+	query := `SELECT id, url, chain_id, height, body, attempts, next, error, dead
+			  FROM webhook_deliveries WHERE id = ?`
+	rows, err := s.db.Query(query, id)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	deliveries := s.scanDeliveries(rows)
+	if len(deliveries) > 0 {
+		return deliveries[0]
+	}
+	return nil
+}
+
+func (s SQLiteDriver) GetDueEndpoints(now time.Time) []string {
+	query := `SELECT DISTINCT url FROM webhook_deliveries WHERE dead = 0 AND next <= ?`
+	rows, err := s.db.Query(query, now.Unix())
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			utils.L.Fatal(err)
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+func (s SQLiteDriver) GetDueDeliveries(url string, now time.Time, count int) []*webhook.Delivery {
+	defer metrics.StorageLatency.Since("get_due_deliveries", time.Now())
+
+	query := `SELECT id, url, chain_id, height, body, attempts, next, error, dead
+			  FROM webhook_deliveries WHERE url = ? AND dead = 0 AND next <= ? ORDER BY id LIMIT ?`
+	rows, err := s.db.Query(query, url, now.Unix(), count)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanDeliveries(rows)
+}
+
+func (s SQLiteDriver) GetDeadDeliveries(count int) []*webhook.Delivery {
+	query := `SELECT id, url, chain_id, height, body, attempts, next, error, dead
+			  FROM webhook_deliveries WHERE dead = 1 ORDER BY id DESC LIMIT ?`
+	if count <= 0 {
+		count = -1
+	}
+	rows, err := s.db.Query(query, count)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanDeliveries(rows)
+}
+
 func (s SQLiteDriver) CountOfPeers() int {
 	query := `SELECT COUNT(addr) FROM peers`
 
@@ -230,13 +538,15 @@ func (s SQLiteDriver) scanPeers(rows *sql.Rows) []*network.Peer {
 		var addr string
 		var rank int
 		var last int64
-		err := rows.Scan(&addr, &rank, &last)
+		var id sql.NullString
+		err := rows.Scan(&addr, &rank, &last, &id)
 		if err != nil {
 			utils.L.Fatal(err)
 		}
 		peer := network.NewPeer(addr, rank)
 		peer.IsServer = true
 		peer.Last = time.Unix(last, 0)
+		peer.SetID(id.String)
 		peers = append(peers, peer)
 	}
 
@@ -244,7 +554,7 @@ func (s SQLiteDriver) scanPeers(rows *sql.Rows) []*network.Peer {
 }
 
 func (s SQLiteDriver) GetPeer(addr string) *network.Peer {
-	query := `SELECT addr, rank, last FROM peers WHERE addr = ?`
+	query := `SELECT addr, rank, last, node_id FROM peers WHERE addr = ?`
 	rows, err := s.db.Query(query, addr)
 	if err != nil {
 		utils.L.Fatal(err)
@@ -258,12 +568,29 @@ func (s SQLiteDriver) GetPeer(addr string) *network.Peer {
 	return nil
 }
 
+func (s SQLiteDriver) GetPeerByID(id string) *network.Peer {
+	query := `SELECT addr, rank, last, node_id FROM peers WHERE node_id = ?`
+	rows, err := s.db.Query(query, id)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	peers := s.scanPeers(rows)
+	if len(peers) > 0 {
+		return peers[0]
+	}
+	return nil
+}
+
 func (s SQLiteDriver) GetPeers(count int) []*network.Peer {
+	defer metrics.StorageLatency.Since("get_peers", time.Now())
+
 	var query string
 	if count == 0 {
-		query = `SELECT addr, rank, last FROM peers ORDER BY rank`
+		query = `SELECT addr, rank, last, node_id FROM peers ORDER BY rank`
 	} else {
-		query = `SELECT addr, rank, last FROM peers ORDER BY rank LIMIT ?`
+		query = `SELECT addr, rank, last, node_id FROM peers ORDER BY rank LIMIT ?`
 	}
 
 	rows, err := s.db.Query(query, count)
@@ -276,16 +603,50 @@ func (s SQLiteDriver) GetPeers(count int) []*network.Peer {
 }
 
 // TODO: need a better error check
+// Peers with a verified node ID are keyed by the ID,
+// so the rank of a node is kept when its address changes
 func (s SQLiteDriver) SavePeer(peer *network.Peer) {
-	query := `INSERT INTO peers VALUES (?, ?, ?)`
+	defer metrics.StorageLatency.Since("save_peer", time.Now())
+
+	nodeID := peer.ID()
+	if len(nodeID) > 0 {
+		if known := s.GetPeerByID(nodeID); known != nil {
+			query := `DELETE FROM peers WHERE addr = ? AND (node_id IS NULL OR node_id != ?)`
+			if _, err := s.db.Exec(query, peer.Addr, nodeID); err != nil {
+				utils.L.Warningf("failed to update peer: %v", err)
+				return
+			}
+
+			query = `UPDATE peers SET addr=?, last=? WHERE node_id=?`
+			if _, err := s.db.Exec(query, peer.Addr, peer.Last.Unix(), nodeID); err != nil {
+				utils.L.Warningf("failed to update peer: %v", err)
+			}
+			peer.Rank = known.Rank
+			return
+		}
+
+		// another node used the address, its rank is not taken over
+		query := `DELETE FROM peers WHERE addr = ? AND node_id IS NOT NULL AND node_id != ?`
+		if _, err := s.db.Exec(query, peer.Addr, nodeID); err != nil {
+			utils.L.Warningf("failed to update peer: %v", err)
+			return
+		}
+	}
 
-	_, err := s.db.Exec(query, peer.Addr, peer.Rank, peer.Last.Unix())
+	var id interface{}
+	if len(nodeID) > 0 {
+		id = nodeID
+	}
+
+	query := `INSERT INTO peers (addr, rank, last, node_id) VALUES (?, ?, ?, ?)`
+
+	_, err := s.db.Exec(query, peer.Addr, peer.Rank, peer.Last.Unix(), id)
 	if err != nil {
 		utils.L.Debug("peer already exist, update")
-		query = `UPDATE peers SET last=? WHERE addr=?`
-		_, err = s.db.Exec(query, peer.Last.Unix(), peer.Addr)
+		query = `UPDATE peers SET last=?, node_id=COALESCE(?, node_id) WHERE addr=?`
+		_, err = s.db.Exec(query, peer.Last.Unix(), id, peer.Addr)
 		if err != nil {
-			utils.L.Warning("failed to update peer: %v", err)
+			utils.L.Warningf("failed to update peer: %v", err)
 		}
 	}
 
@@ -295,7 +656,7 @@ func (s SQLiteDriver) DeletePeer(peer *network.Peer) {
 	query := `DELETE FROM peers WHERE addr = ?`
 	_, err := s.db.Exec(query, peer.Addr)
 	if err != nil {
-		utils.L.Warning("failed to delete a peer, %v", err)
+		utils.L.Warningf("failed to delete a peer, %v", err)
 	}
 }
 
@@ -306,6 +667,9 @@ func (s SQLiteDriver) CleanChain(chain *blockchain.Chain) {
 	if err != nil {
 		utils.L.Warning("failed to delete a chain")
 	}
+	if err := s.SetRecovering(chain.ID, false); err != nil {
+		utils.L.Warningf("%v", err)
+	}
 
 	query = `SELECT hash FROM blocks WHERE payload = '*'`
 	rows, err := s.db.Query(query)
@@ -319,8 +683,8 @@ func (s SQLiteDriver) CleanChain(chain *blockchain.Chain) {
 			utils.L.Fatal(err)
 		}
 
-		if err := os.Remove(viper.GetString("data.root") + hash); err != nil {
-			utils.L.Warning("%v", err)
+		if err := os.Remove(utils.GetString("data.root") + hash); err != nil {
+			utils.L.Warningf("%v", err)
 		}
 	}
 
@@ -328,22 +692,82 @@ func (s SQLiteDriver) CleanChain(chain *blockchain.Chain) {
 
 	_, err = s.db.Exec(query, chain.Ref)
 	if err != nil {
-		utils.L.Warning("failed to delete blocks of chain %v", chain.ID)
+		utils.L.Warningf("failed to delete blocks of chain %v", chain.ID)
 	}
 }
 
+// Tables other than peers, created if missing, so tables introduced
+// after the database was created are added by upgrading
+const sqliteSchema = `
+		CREATE TABLE IF NOT EXISTS chains (
+			id			INTEGER PRIMARY KEY,
+			chain_id 	TEXT NOT NULL,
+			wif      	TEXT NOT NULL,
+			count		INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS blocks (
+			height 		INTEGER NOT NULL,
+			time 		INTEGER NOT NULL,
+			hash 		TEXT NOT NULL,
+			prev_hash 	TEXT NOT NULL,
+			signature 	TEXT NOT NULL,
+			payload 	TEXT NOT NULL,
+			chain_id	INTEGER NOT NULL,
+			FOREIGN KEY (chain_id) REFERENCES chains(id)
+		);
+		CREATE TABLE IF NOT EXISTS discovered_chains (
+			chain_id	TEXT PRIMARY KEY,
+			genesis		TEXT NOT NULL,
+			count		INTEGER NOT NULL DEFAULT 0,
+			last		INTEGER
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS chains_chain_id ON chains(chain_id);
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id			INTEGER PRIMARY KEY,
+			url			TEXT NOT NULL,
+			chain_id	TEXT NOT NULL,
+			height		INTEGER NOT NULL,
+			body		TEXT NOT NULL,
+			attempts	INTEGER NOT NULL DEFAULT 0,
+			next		INTEGER NOT NULL,
+			error		TEXT NOT NULL DEFAULT '',
+			dead		INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS webhook_deliveries_next ON webhook_deliveries(dead, next);
+		CREATE TABLE IF NOT EXISTS recovering_chains (
+			chain_id	TEXT PRIMARY KEY
+		);
+		CREATE INDEX IF NOT EXISTS blocks_height ON blocks(height);
+		CREATE INDEX IF NOT EXISTS blocks_hash ON blocks(hash);
+`
+
+// Peers are keyed by node ID once verified, others are known by
+// address only, and an address belongs to one peer at a time
+const peersSchema = `
+		CREATE TABLE peers (
+			id		INTEGER PRIMARY KEY,
+			node_id	TEXT,
+			addr 	TEXT NOT NULL,
+			rank 	INTEGER,
+			last 	INTEGER
+		);
+		CREATE UNIQUE INDEX peers_node_id ON peers(node_id);
+		CREATE UNIQUE INDEX peers_addr ON peers(addr);
+`
+
 func sqliteMigrate() {
-	file := viper.GetString("data.file")
+	file := utils.GetString("data.file")
 	_, err := os.Stat(file)
 
-	// do nothing if file exists
+	// only upgrade the schema if file exists
 	if err == nil {
+		sqliteUpgrade(file)
 		return
 	}
 
 	// cannot process if file with errors which is not "not exist"
 	if !os.IsNotExist(err) {
-		utils.L.Error("%v", err)
+		utils.L.Errorf("%v", err)
 		return
 	}
 
@@ -358,40 +782,84 @@ func sqliteMigrate() {
 	}
 	defer func() { _ = db.Close() }()
 
-	query := `
-		CREATE TABLE chains (
-			id			INTEGER PRIMARY KEY,
-			chain_id 	TEXT NOT NULL,
-			wif      	TEXT NOT NULL,
-			count		INTEGER NOT NULL DEFAULT 0
-		);
-		CREATE TABLE blocks (
-			height 		INTEGER NOT NULL,
-			time 		INTEGER NOT NULL,
-			hash 		TEXT NOT NULL,
-			prev_hash 	TEXT NOT NULL,
-			signature 	TEXT NOT NULL,
-			payload 	TEXT NOT NULL,
-			chain_id	INTEGER NOT NULL,
-			FOREIGN KEY (chain_id) REFERENCES chains(id)
-		);
-		CREATE TABLE peers (
-			addr 	TEXT PRIMARY KEY,
-			rank 	INTEGER,
-			last 	INTEGER
-		);
-		CREATE UNIQUE INDEX chains_chain_id ON chains(chain_id);
-		CREATE INDEX blocks_height ON blocks(height);
-		CREATE INDEX blocks_hash ON blocks(hash);
-	`
-	_, err = db.Exec(query)
+	_, err = db.Exec(peersSchema + sqliteSchema)
 	if err != nil {
-		utils.L.Warning("%v", err)
+		utils.L.Warningf("%v", err)
 	} else {
 		utils.L.Info("database created")
 	}
 }
 
+// Add columns introduced after the database was created
+func sqliteUpgrade(file string) {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query(`PRAGMA table_info(peers)`)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+
+	columns := map[string]bool{}
+	var primary string
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, kind string
+		var value sql.NullString
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &value, &pk); err != nil {
+			utils.L.Fatal(err)
+		}
+		columns[name] = true
+		if pk > 0 {
+			primary = name
+		}
+	}
+	_ = rows.Close()
+
+	// the file exists without tables
+	if len(columns) == 0 {
+		if _, err := db.Exec(peersSchema); err != nil {
+			utils.L.Fatal(err)
+		}
+		columns["node_id"] = true
+		primary = "id"
+	}
+
+	if !columns["node_id"] {
+		query := `
+			ALTER TABLE peers ADD COLUMN node_id TEXT;
+			CREATE UNIQUE INDEX peers_node_id ON peers(node_id);
+		`
+		if _, err := db.Exec(query); err != nil {
+			utils.L.Fatal(err)
+		}
+		utils.L.Info("database upgraded: peers.node_id")
+	}
+
+	// peers were keyed by address
+	if primary == "addr" {
+		query := `
+			DROP INDEX IF EXISTS peers_node_id;
+			ALTER TABLE peers RENAME TO peers_by_addr;
+		` + peersSchema + `
+			INSERT INTO peers (node_id, addr, rank, last)
+			SELECT node_id, addr, rank, last FROM peers_by_addr;
+			DROP TABLE peers_by_addr;
+		`
+		if _, err := db.Exec(query); err != nil {
+			utils.L.Fatal(err)
+		}
+		utils.L.Info("database upgraded: peers are keyed by node ID")
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		utils.L.Fatal(err)
+	}
+}
+
 func sqlitePrune() {
-	_ = os.Remove(viper.GetString("data.file"))
+	_ = os.Remove(utils.GetString("data.file"))
 }
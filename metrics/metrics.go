@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics of a running node, reported by node status and
+// exposed in Prometheus text format
+type Counter struct {
+	name  string
+	help  string
+	value uint64
+}
+
+// Counters split by one label, such as message type
+type CounterVec struct {
+	name   string
+	help   string
+	label  string
+	values map[string]uint64
+	lock   sync.Mutex
+}
+
+type Gauge struct {
+	name  string
+	help  string
+	value int64
+}
+
+// Gauges split by one label, values are replaced as a whole
+type GaugeVec struct {
+	name   string
+	help   string
+	label  string
+	values map[string]int64
+	lock   sync.Mutex
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Histograms of seconds split by one label
+type HistogramVec struct {
+	name    string
+	help    string
+	label   string
+	buckets []float64
+	values  map[string]*histogram
+	lock    sync.Mutex
+}
+
+var (
+	BytesReceived      = NewCounter("ifc_received_bytes_total", "Bytes received from peers.")
+	BytesSent          = NewCounter("ifc_sent_bytes_total", "Bytes sent to peers.")
+	MessagesReceived   = NewCounterVec("ifc_messages_received_total", "Messages received from peers by type.", "type")
+	MessagesSent       = NewCounterVec("ifc_messages_sent_total", "Messages sent to peers by type.", "type")
+	Peers              = NewGaugeVec("ifc_peers", "Connected peers by direction.", "direction")
+	ValidationFailures = NewCounterVec("ifc_block_validation_failures_total", "Blocks from peers failed validation by error code.", "code")
+	BlocksSaved        = NewCounterVec("ifc_blocks_saved_total", "Blocks saved by chain.", "chain")
+	StorageLatency     = NewHistogramVec("ifc_storage_duration_seconds", "Latency of storage operations.", "operation", DefaultBuckets)
+	BroadcastQueue     = NewGauge("ifc_broadcast_queue_depth", "Announcements and gossips waiting to be relayed.")
+	WebhookDeliveries  = NewCounterVec("ifc_webhook_deliveries_total", "Webhook delivery attempts by result.", "result")
+)
+
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+var registry []interface{}
+var registryLock sync.Mutex
+
+func register(metric interface{}) {
+	registryLock.Lock()
+	registry = append(registry, metric)
+	registryLock.Unlock()
+}
+
+func NewCounter(name string, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	register(c)
+	return c
+}
+
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.value, n)
+}
+
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func NewCounterVec(name string, help string, label string) *CounterVec {
+	c := &CounterVec{name: name, help: help, label: label, values: map[string]uint64{}}
+	register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(label string) {
+	c.Add(label, 1)
+}
+
+func (c *CounterVec) Add(label string, n uint64) {
+	c.lock.Lock()
+	c.values[label] += n
+	c.lock.Unlock()
+}
+
+// Copy of values of all labels
+func (c *CounterVec) Values() map[string]uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := map[string]uint64{}
+	for k, v := range c.values {
+		result[k] = v
+	}
+	return result
+}
+
+func NewGauge(name string, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Inc() {
+	atomic.AddInt64(&g.value, 1)
+}
+
+func (g *Gauge) Dec() {
+	atomic.AddInt64(&g.value, -1)
+}
+
+func (g *Gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+func NewGaugeVec(name string, help string, label string) *GaugeVec {
+	g := &GaugeVec{name: name, help: help, label: label, values: map[string]int64{}}
+	register(g)
+	return g
+}
+
+func (g *GaugeVec) Set(values map[string]int64) {
+	g.lock.Lock()
+	g.values = values
+	g.lock.Unlock()
+}
+
+func NewHistogramVec(name string, help string, label string, buckets []float64) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, label: label, buckets: buckets, values: map[string]*histogram{}}
+	register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(label string, value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	v := h.values[label]
+	if v == nil {
+		v = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[label] = v
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			v.counts[i]++
+		}
+	}
+	v.sum += value
+	v.count++
+}
+
+// Observe seconds elapsed from start, used as 'defer h.Since(label, time.Now())'
+func (h *HistogramVec) Since(label string, start time.Time) {
+	h.Observe(label, time.Since(start).Seconds())
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]uint64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]int64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*histogram:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
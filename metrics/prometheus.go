@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func header(w io.Writer, name string, help string, kind string) {
+	_, _ = fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, kind)
+}
+
+func sample(w io.Writer, name string, label string, value string, number interface{}) {
+	if len(label) == 0 {
+		_, _ = fmt.Fprintf(w, "%v %v\n", name, number)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%v{%v=\"%v\"} %v\n", name, label, labelEscaper.Replace(value), number)
+}
+
+func (c *Counter) write(w io.Writer) {
+	header(w, c.name, c.help, "counter")
+	sample(w, c.name, "", "", c.Value())
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	header(w, c.name, c.help, "counter")
+	values := c.Values()
+	for _, k := range sortedKeys(values) {
+		sample(w, c.name, c.label, k, values[k])
+	}
+}
+
+func (g *Gauge) write(w io.Writer) {
+	header(w, g.name, g.help, "gauge")
+	sample(w, g.name, "", "", g.Value())
+}
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	header(w, g.name, g.help, "gauge")
+	for _, k := range sortedKeys(g.values) {
+		sample(w, g.name, g.label, k, g.values[k])
+	}
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	header(w, h.name, h.help, "histogram")
+	for _, k := range sortedKeys(h.values) {
+		v := h.values[k]
+		value := labelEscaper.Replace(k)
+		for i, bound := range h.buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			_, _ = fmt.Fprintf(w, "%v_bucket{%v=\"%v\",le=\"%v\"} %v\n", h.name, h.label, value, le, v.counts[i])
+		}
+		_, _ = fmt.Fprintf(w, "%v_bucket{%v=\"%v\",le=\"+Inf\"} %v\n", h.name, h.label, value, v.count)
+		_, _ = fmt.Fprintf(w, "%v_sum{%v=\"%v\"} %v\n", h.name, h.label, value, v.sum)
+		_, _ = fmt.Fprintf(w, "%v_count{%v=\"%v\"} %v\n", h.name, h.label, value, v.count)
+	}
+}
+
+// Write all metrics in Prometheus text exposition format
+func Write(w io.Writer) {
+	registryLock.Lock()
+	metrics := append([]interface{}{}, registry...)
+	registryLock.Unlock()
+
+	for _, m := range metrics {
+		if writer, ok := m.(interface{ write(io.Writer) }); ok {
+			writer.write(w)
+		}
+	}
+}
+
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	Write(w)
+}
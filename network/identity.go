@@ -0,0 +1,63 @@
+package network
+
+import (
+	"crypto/rand"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/mr-tron/base58"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var localKey *crypto.Key
+var localKeyOnce sync.Once
+
+// Node identity is a key saved as WIF at 'node.key',
+// a new one will be created at the first time
+func LocalKey() *crypto.Key {
+	localKeyOnce.Do(func() {
+		file := utils.GetString("node.key")
+		content, err := ioutil.ReadFile(file)
+		if err == nil {
+			localKey, err = crypto.FromWIF(strings.TrimSpace(string(content)))
+			if err != nil {
+				utils.L.Fatalf("invalid node key %v: %v", file, err)
+			}
+			return
+		}
+
+		if !os.IsNotExist(err) {
+			utils.L.Fatal(err)
+		}
+
+		localKey = crypto.NewKey()
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			utils.L.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(localKey.ToWIF()), 0600); err != nil {
+			utils.L.Fatal(err)
+		}
+		utils.L.Infof("create node key at %v", file)
+	})
+	return localKey
+}
+
+func LocalNodeID() string {
+	return LocalKey().ToAddress()
+}
+
+// Content to be signed for proving the identity to the verifier
+func AuthMessage(challenge string, verifierID string) []byte {
+	return []byte("ifc-auth:" + challenge + ":" + verifierID)
+}
+
+func newChallenge() string {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		utils.L.Fatal(err)
+	}
+	return base58.Encode(nonce)
+}
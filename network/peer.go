@@ -1,9 +1,11 @@
 package network
 
 import (
+	"github.com/Infnote/infnotechain/metrics"
 	"github.com/Infnote/infnotechain/utils"
 	"github.com/gorilla/websocket"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -14,14 +16,43 @@ type Peer struct {
 	Recv     chan []byte
 	Send     chan []byte
 	IsServer bool
+	Encoding string
+
+	Challenge string
+
+	// set when the peer is of another network, it is closed
+	// after the error is sent
+	Rejected bool
+
+	// states changed by messages and read by other services,
+	// only accessed by methods with the lock
+	state stateOfPeer
+	lock  sync.RWMutex
 
 	server *Server
 	conn   *websocket.Conn
+
+	// Send is closed once, writers hold the read lock
+	// so it is never closed while they are sending
+	closed    bool
+	closeLock sync.RWMutex
+}
+
+type stateOfPeer struct {
+	// address of node key, saved or set after the peer
+	// signed the challenge we sent
+	id       string
+	verified bool
+
+	// chains the peer subscribed, nil if never subscribed
+	subscriptions map[string]bool
+	discovery     bool
 }
 
 type Storage interface {
 	CountOfPeers() int
 	GetPeer(addr string) *Peer
+	GetPeerByID(id string) *Peer
 	GetPeers(count int) []*Peer
 	SavePeer(peer *Peer)
 	DeletePeer(peer *Peer)
@@ -43,12 +74,62 @@ func SharedStorage() Storage {
 
 func NewPeer(addr string, rank int) *Peer {
 	return &Peer{
-		Addr: addr,
-		Rank: rank,
-		Last: time.Now(),
-		Recv: make(chan []byte),
-		Send: make(chan []byte),
+		Addr:      addr,
+		Rank:      rank,
+		Last:      time.Now(),
+		Recv:      make(chan []byte),
+		Send:      make(chan []byte),
+		Challenge: newChallenge(),
+	}
+}
+
+// Node ID of the peer, it is verified only if Verified is true
+func (c *Peer) ID() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.state.id
+}
+
+// Node ID known before verified, such as the one saved
+func (c *Peer) SetID(id string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.state.id = id
+}
+
+func (c *Peer) Verified() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.state.verified
+}
+
+// Mark the peer verified as the node, online peers are keyed by the ID
+// after verified, false if the node is connected by another peer
+func (c *Peer) Verify(id string) bool {
+	if c.server != nil && !c.server.register(c, id) {
+		return false
 	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.state.id = id
+	c.state.verified = true
+	return true
+}
+
+// Chains the peer subscribed, nil if never subscribed, and
+// whether it discovers new chains
+func (c *Peer) Subscriptions() (map[string]bool, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.state.subscriptions, c.state.discovery
+}
+
+// Subscriptions are replaced but never changed after set
+func (c *Peer) Subscribe(subscriptions map[string]bool, discovery bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.state.subscriptions = subscriptions
+	c.state.discovery = discovery
 }
 
 func (c *Peer) Save() {
@@ -56,6 +137,42 @@ func (c *Peer) Save() {
 	instance.SavePeer(c)
 }
 
+// Close the connection with a websocket close frame
+func (c *Peer) Close() {
+	c.closeLock.Lock()
+	defer c.closeLock.Unlock()
+	if !c.closed {
+		c.closed = true
+		safeClose(c.Send)
+	}
+}
+
+// Whether the connection is closed or closing
+func (c *Peer) IsClosed() bool {
+	c.closeLock.RLock()
+	defer c.closeLock.RUnlock()
+	return c.closed
+}
+
+// Queue a message to the writing loop, false if the peer is closed
+// or the message is not taken before the timeout
+func (c *Peer) Write(data []byte, timeout time.Duration) bool {
+	c.closeLock.RLock()
+	defer c.closeLock.RUnlock()
+	if c.closed {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case c.Send <- data:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
 func (c *Peer) read() {
 	defer func() {
 		c.server.Out <- c
@@ -69,12 +186,13 @@ func (c *Peer) read() {
 		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				utils.L.Debugf("connection closed unexpectedly: %v", err)
+				utils.L.With(utils.Fields{"peer": c.Addr}).Debugf("connection closed unexpectedly: %v", err)
 			}
-			safeClose(c.Send)
+			c.Close()
 			return
 		}
-		utils.L.Debugf("message received: %v bytes", len(data))
+		utils.L.With(utils.Fields{"peer": c.Addr}).Debugf("message received: %v bytes", len(data))
+		metrics.BytesReceived.Add(uint64(len(data)))
 		c.Recv <- data
 	}
 }
@@ -94,18 +212,27 @@ func (c *Peer) write() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
+			w, err := c.conn.NextWriter(frameType(msg))
 			if err != nil {
 				return
 			}
 
-			utils.L.Debugf("writing message: %v bytes", len(msg))
+			utils.L.With(utils.Fields{"peer": c.Addr}).Debugf("writing message: %v bytes", len(msg))
 			_, _ = w.Write(msg)
+			metrics.BytesSent.Add(uint64(len(msg)))
 			_ = w.Close()
 		}
 	}
 }
 
+// JSON messages are always objects, others are sent as binary
+func frameType(msg []byte) int {
+	if len(msg) > 0 && msg[0] == '{' {
+		return websocket.TextMessage
+	}
+	return websocket.BinaryMessage
+}
+
 func safeClose(c chan []byte) {
 	defer func() {
 		recover()
@@ -114,9 +241,15 @@ func safeClose(c chan []byte) {
 }
 
 func inbound(server *Server, w http.ResponseWriter, r *http.Request) {
+	if err := acceptable(r); err != nil {
+		utils.L.With(utils.Fields{"peer": r.RemoteAddr}).Warningf("refuse peer %v: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		utils.L.Warning("%v", err)
+		utils.L.Warningf("%v", err)
 		return
 	}
 
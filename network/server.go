@@ -1,22 +1,37 @@
 package network
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/Infnote/infnotechain/utils"
 	"github.com/gorilla/websocket"
-	"github.com/spf13/viper"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 )
 
 type Server struct {
-	Peers map[string]*Peer
-	In    chan *Peer
-	Out   chan *Peer
+	In  chan *Peer
+	Out chan *Peer
+
+	// online peers are changed by the peer service and
+	// read by handlers of other services, verified peers
+	// are indexed by node ID
+	peers map[*Peer]bool
+	ids   map[string]*Peer
+	lock  sync.RWMutex
+
+	http *http.Server
 }
 
 const BufferSize = 1024 * 1024 * 2
 
+// Network of the node sent when dialing, peers without it are mainnet nodes
+const NetworkHeader = "X-Infnote-Network"
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:    BufferSize,
 	WriteBufferSize:   BufferSize,
@@ -36,22 +51,105 @@ var dialer = &websocket.Dialer{
 
 func NewServer() *Server {
 	return &Server{
-		map[string]*Peer{},
-		make(chan *Peer),
-		make(chan *Peer),
+		peers: map[*Peer]bool{},
+		ids:   map[string]*Peer{},
+		In:    make(chan *Peer),
+		Out:   make(chan *Peer),
+		http: &http.Server{
+			Addr: fmt.Sprintf(
+				"%v:%v",
+				utils.GetString("server.host"),
+				utils.GetString("server.port")),
+		},
+	}
+}
+
+// Snapshot of online peers, safe to use without holding the lock
+func (s *Server) Peers() []*Peer {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	peers := make([]*Peer, 0, len(s.peers))
+	for peer := range s.peers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+// Online peer of the address, nil if not connected
+func (s *Server) Peer(addr string) *Peer {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for peer := range s.peers {
+		if peer.Addr == addr {
+			return peer
+		}
+	}
+	return nil
+}
+
+// Online peer verified as the node, nil if not connected
+func (s *Server) PeerByID(id string) *Peer {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.ids[id]
+}
+
+func (s *Server) PeerCount() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.peers)
+}
+
+func (s *Server) AddPeer(peer *Peer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.peers[peer] = true
+}
+
+func (s *Server) RemovePeer(peer *Peer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.peers, peer)
+	for id, p := range s.ids {
+		if p == peer {
+			delete(s.ids, id)
+		}
+	}
+}
+
+// Key an online peer by its verified node ID, a node is only
+// connected once no matter which addresses it uses
+func (s *Server) register(peer *Peer, id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.peers[peer] {
+		return true
+	}
+	if other := s.ids[id]; other != nil && other != peer {
+		return false
 	}
+	s.ids[id] = peer
+	return true
 }
 
 func (s *Server) Connect(peer *Peer) error {
-	conn, _, err := dialer.Dial(peer.Addr, nil)
+	if utils.IsRegtest() && !isLocal(peer.Addr) {
+		utils.L.Warningf("regtest only connects local peers: %v", peer.Addr)
+		return errors.New("regtest only connects local peers")
+	}
+
+	conn, _, err := dialer.Dial(peer.Addr, http.Header{NetworkHeader: {utils.Network()}})
 	if err != nil {
-		utils.L.Warningf("failed to connect peer: %v", err)
+		utils.L.With(utils.Fields{"peer": peer.Addr}).Warningf("failed to connect peer: %v", err)
 		return err
 	}
 	peer.server = s
 	peer.conn = conn
 	peer.Last = time.Now()
 	peer.IsServer = true
+	if len(peer.Challenge) == 0 {
+		peer.Challenge = newChallenge()
+	}
 	peer.Save()
 
 	s.In <- peer
@@ -67,12 +165,47 @@ func (s *Server) Serve() {
 		inbound(s, writer, request)
 	})
 
-	err := http.ListenAndServe(
-		fmt.Sprintf(
-			"%v:%v",
-			viper.GetString("server.host"),
-			viper.GetString("server.port")), nil)
-	if err != nil {
+	err := s.http.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
 		utils.L.Fatal(err)
 	}
 }
+
+// Stop accepting peers, connected peers need to be closed separately
+// since websocket connections are not tracked by http server
+func (s *Server) Shutdown(ctx context.Context) {
+	if err := s.http.Shutdown(ctx); err != nil {
+		utils.L.Warningf("failed to shutdown server: %v", err)
+	}
+}
+
+// Whether the peer is of the same network, checked before upgrading
+// and again by Info
+func acceptable(r *http.Request) error {
+	name := r.Header.Get(NetworkHeader)
+	if len(name) == 0 {
+		name = utils.Mainnet
+	}
+	if name != utils.Network() {
+		return fmt.Errorf("only accept peers of %v", utils.Network())
+	}
+	if utils.IsRegtest() && !isLocal(r.RemoteAddr) {
+		return errors.New("regtest only accepts local peers")
+	}
+	return nil
+}
+
+// Address is a websocket URL or host:port on loopback
+func isLocal(addr string) bool {
+	host := addr
+	if u, err := url.Parse(addr); err == nil && len(u.Host) > 0 {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,88 @@
+package protocol
+
+import (
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/utils"
+)
+
+// Blocks are relayed by announcing the tip of a chain instead of pushing
+// the whole block, peers pull the missing blocks through download scheduler.
+var AnnounceChannel = make(chan *AnnounceBlock)
+
+type AnnounceBlock struct {
+	ChainID string      `json:"chain_id"`
+	Height  uint64      `json:"height"`
+	Hash    string      `json:"hash"`
+	Sender  interface{} `json:"-"`
+}
+
+func NewAnnounceBlock(block *blockchain.Block) *AnnounceBlock {
+	return &AnnounceBlock{
+		ChainID: block.ChainID(),
+		Height:  block.Height,
+		Hash:    block.Hash,
+	}
+}
+
+// Put an announcement of the block into relay queue
+// the announcement will not be sent back to sender
+func Announce(block *blockchain.Block, sender interface{}) {
+	announce := NewAnnounceBlock(block)
+	announce.Sender = sender
+	metrics.BroadcastQueue.Inc()
+	go func() {
+		AnnounceChannel <- announce
+		metrics.BroadcastQueue.Dec()
+	}()
+}
+
+func (b AnnounceBlock) Validate() *Error {
+	if len(b.ChainID) == 0 || len(b.Hash) == 0 {
+		return BadRequestError("'chain_id' and 'hash' are required")
+	}
+
+	if err := checkTrusted(b.Sender); err != nil {
+		return err
+	}
+
+	// announcements of unknown chains are used for discovery
+	if chain := blockchain.LoadChain(b.ChainID); chain == nil && discoveryPolicy() == DiscoveryNone {
+		return ChainNotAcceptError(b.ChainID)
+	}
+
+	return nil
+}
+
+func (b AnnounceBlock) React() []Behavior {
+	chain := blockchain.LoadChain(b.ChainID)
+	if chain == nil {
+		if request := newRequestChains(unknownChains([]string{b.ChainID})); request != nil {
+			return []Behavior{request}
+		}
+		return nil
+	}
+
+	if chain.Count > b.Height {
+		if block := chain.GetLocalBlock(b.Height); block != nil && block.Hash != b.Hash {
+			utils.L.With(utils.Fields{"chain_id": b.ChainID, "height": b.Height}).
+				Debugf("announced block conflicts with local block at height %v", b.Height)
+		}
+		return nil
+	}
+
+	peer, _ := b.Sender.(*network.Peer)
+	SharedScheduler.Advertise(peer, b.ChainID, b.Height+1)
+	return nil
+}
+
+func (b AnnounceBlock) String() string {
+	var result string
+	result += fmt.Sprintf("====== AnnounceBlock =====\n")
+	result += fmt.Sprintf("[Chain ID] %v\n", b.ChainID)
+	result += fmt.Sprintf("[Height  ] %v\n", b.Height)
+	result += fmt.Sprintf("[Hash    ] %v\n", b.Hash)
+	return result
+}
@@ -0,0 +1,114 @@
+package protocol
+
+import (
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/mr-tron/base58"
+	"sync"
+)
+
+// Proof of node identity, a signature of the challenge and node ID
+// the verifier sent in its Info
+type Auth struct {
+	NodeID    string        `json:"node_id"`
+	Signature string        `json:"signature"`
+	Sender    *network.Peer `json:"-"`
+}
+
+func NewAuth(challenge string, verifierID string) *Auth {
+	key := network.LocalKey()
+	return &Auth{
+		NodeID:    key.ToAddress(),
+		Signature: base58.Encode(key.Sign(network.AuthMessage(challenge, verifierID))),
+	}
+}
+
+func (b Auth) Validate() *Error {
+	if len(b.NodeID) == 0 || len(b.Signature) == 0 {
+		return BadRequestError("'node_id' and 'signature' are required")
+	}
+
+	if b.Sender == nil {
+		return UnauthorizedError("no challenge sent")
+	}
+
+	sig, err := base58.Decode(b.Signature)
+	if err != nil {
+		return UnauthorizedError("invalid signature")
+	}
+
+	if !crypto.Verify(b.NodeID, sig, network.AuthMessage(b.Sender.Challenge, network.LocalNodeID())) {
+		return UnauthorizedError("invalid signature")
+	}
+
+	return nil
+}
+
+func (b Auth) React() []Behavior {
+	logger := utils.L.With(utils.Fields{"peer": b.Sender.Addr})
+	if !b.Sender.Verify(b.NodeID) {
+		logger.Infof("node %v is already connected, peer %v closed", b.NodeID, b.Sender.Addr)
+		b.Sender.Close()
+		return nil
+	}
+	logger.Infof("peer %v verified as %v", b.Sender.Addr, b.NodeID)
+
+	// only peers we connected to are recorded
+	if b.Sender.IsServer {
+		b.Sender.Save()
+	}
+
+	// chains advertised before verified
+	if info := takePendingInfo(b.Sender); info != nil {
+		info.advertise()
+	}
+	return nil
+}
+
+func (b Auth) String() string {
+	var result string
+	result += fmt.Sprintf("========== Auth ==========\n")
+	result += fmt.Sprintf("[Node ID  ] %v\n", b.NodeID)
+	result += fmt.Sprintf("[Signature] %v\n", b.Signature)
+	return result
+}
+
+// Blocks are only accepted from and relayed to verified peers
+// unless 'peers.auth' is disabled
+func IsTrusted(peer *network.Peer) bool {
+	return peer == nil || peer.Verified() || !utils.GetBool("peers.auth")
+}
+
+// Info of peers not verified yet, chains are advertised
+// to the scheduler after the peer is verified
+var pendingInfo = map[*network.Peer]*Info{}
+var pendingInfoLock sync.Mutex
+
+func deferInfo(info *Info) {
+	pendingInfoLock.Lock()
+	defer pendingInfoLock.Unlock()
+	pendingInfo[info.Sender] = info
+}
+
+func takePendingInfo(peer *network.Peer) *Info {
+	pendingInfoLock.Lock()
+	defer pendingInfoLock.Unlock()
+	info := pendingInfo[peer]
+	delete(pendingInfo, peer)
+	return info
+}
+
+// Forget a disconnected peer
+func RemovePeer(peer *network.Peer) {
+	takePendingInfo(peer)
+	SharedScheduler.RemovePeer(peer)
+}
+
+func checkTrusted(sender interface{}) *Error {
+	if peer, ok := sender.(*network.Peer); ok && !IsTrusted(peer) {
+		return UnauthorizedError("peer is not verified")
+	}
+	return nil
+}
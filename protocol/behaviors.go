@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
 	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/utils"
-	"github.com/spf13/viper"
 	"golang.org/x/sys/unix"
 	"net/url"
 	"time"
@@ -25,11 +25,17 @@ type Behavior interface {
 
 // - Declarations
 type Info struct {
-	Version  string            `json:"version"`
-	Peers    int               `json:"peers"`
-	Chains   map[string]uint64 `json:"chains"`
-	Platform map[string]string `json:"platform"`
-	FullNode bool              `json:"full_node"`
+	Version   string            `json:"version"`
+	Network   string            `json:"network,omitempty"`
+	Peers     int               `json:"peers"`
+	Chains    map[string]uint64 `json:"chains"`
+	Platform  map[string]string `json:"platform"`
+	FullNode  bool              `json:"full_node"`
+	Encodings []string          `json:"encodings,omitempty"`
+	Retained  map[string]uint64 `json:"retained,omitempty"`
+	NodeID    string            `json:"node_id,omitempty"`
+	Challenge string            `json:"challenge,omitempty"`
+	Sender    *network.Peer     `json:"-"`
 }
 
 type RequestPeers struct {
@@ -48,6 +54,7 @@ type ResponsePeers struct {
 
 type ResponseBlocks struct {
 	Blocks []json.RawMessage `json:"blocks"`
+	Sender *network.Peer     `json:"-"`
 	blocks []*blockchain.Block
 }
 
@@ -57,6 +64,7 @@ func (b Info) String() string {
 	var result string
 	result += fmt.Sprintf("========== Info ==========\n")
 	result += fmt.Sprintf("[Version  ] %v\n", b.Version)
+	result += fmt.Sprintf("[Network  ] %v\n", b.Network)
 	result += fmt.Sprintf("[Peers    ] %v\n", b.Peers)
 	result += fmt.Sprintf("[Chains   ]\n")
 	for id, count := range b.Chains {
@@ -64,6 +72,12 @@ func (b Info) String() string {
 	}
 	result += fmt.Sprintf("[Platform ] %v\n", b.Platform)
 	result += fmt.Sprintf("[Full Node] %v\n", b.FullNode)
+	if !b.FullNode {
+		result += fmt.Sprintf("[Retained ] %v\n", b.Retained)
+	}
+	result += fmt.Sprintf("[Encodings] %v\n", b.Encodings)
+	result += fmt.Sprintf("[Node ID  ] %v\n", b.NodeID)
+	result += fmt.Sprintf("[Challenge] %v\n", b.Challenge)
 	return result
 }
 
@@ -124,19 +138,37 @@ func newSysInfo() map[string]string {
 func NewInfo() *Info {
 	chains := blockchain.LoadAllChains()
 	chainMap := map[string]uint64{}
+	var retained map[string]uint64
+	if blockchain.IsLightNode() {
+		retained = map[string]uint64{}
+	}
 	for _, chain := range chains {
 		chainMap[chain.ID] = chain.Count
+		if retained != nil {
+			retained[chain.ID] = chain.RetainedFrom()
+		}
 	}
 
 	return &Info{
-		Version:  "1.1",
-		Peers:    network.SharedStorage().CountOfPeers(),
-		Chains:   chainMap,
-		Platform: newSysInfo(),
-		FullNode: true,
+		Version:   ProtocolVersion,
+		Network:   utils.Network(),
+		Peers:     network.SharedStorage().CountOfPeers(),
+		Chains:    chainMap,
+		Platform:  newSysInfo(),
+		FullNode:  !blockchain.IsLightNode(),
+		Retained:  retained,
+		Encodings: supportedEncodings(),
+		NodeID:    network.LocalNodeID(),
 	}
 }
 
+// Info with the challenge the peer needs to sign for authentication
+func NewInfoFor(peer *network.Peer) *Info {
+	info := NewInfo()
+	info.Challenge = peer.Challenge
+	return info
+}
+
 // - Serializations
 func (b ResponseBlocks) Serialize() []byte {
 	var blocks []json.RawMessage
@@ -153,14 +185,31 @@ func (b ResponseBlocks) Serialize() []byte {
 // - Validations
 func (b Info) Validate() *Error {
 	//version, err := strconv.ParseFloat(b.Version, 32)
-	if b.Version != "1.1" {
+	if b.Version != ProtocolVersion {
 		return IncompatibleProtocolVersionError("only accept v1.1 protocol")
 	}
 
+	// peers not sending network are mainnet nodes of earlier versions,
+	// peers of another network are disconnected after the error
+	name := b.Network
+	if len(name) == 0 {
+		name = utils.Mainnet
+	}
+	if name != utils.Network() {
+		if b.Sender != nil {
+			b.Sender.Rejected = true
+		}
+		return IncompatibleNetworkError(fmt.Sprintf("only accept peers of %v", utils.Network()))
+	}
+
 	if b.Peers < 0 {
 		return BadRequestError("'peers' needs to be a non-negative number")
 	}
 
+	if len(b.NodeID) > 0 && b.NodeID == network.LocalNodeID() {
+		return BadRequestError("connected to self")
+	}
+
 	return nil
 }
 
@@ -202,26 +251,42 @@ func (b ResponsePeers) Validate() *Error {
 }
 
 func (b *ResponseBlocks) Validate() *Error {
+	if err := checkTrusted(b.Sender); err != nil {
+		return err
+	}
+
+	// blocks may be decoded already from protobuf message
+	blocks := b.blocks
+	b.blocks = nil
 	for _, v := range b.Blocks {
 		block, err := blockchain.DeserializeBlock(v)
 		if err != nil {
 			return JSONDecodeError(err.Error())
 		}
+		blocks = append(blocks, block)
+	}
 
+	for _, block := range blocks {
 		if err := block.Validate(); err != nil {
 			utils.L.Debugf("a invalid block: %v", err.Error())
+			metrics.ValidationFailures.Inc(err.Code())
 			return BlockValidationError(err)
 		}
 
+		// blocks looked up by hash may belong to chains not saved here
+		if deliverFetched(block) {
+			continue
+		}
+
 		chain := blockchain.LoadChain(block.ChainID())
 		if chain == nil {
 			return ChainNotAcceptError(fmt.Sprintf("recovered chain ID: %v", block.ChainID()))
 		}
 
-		verr := chain.CacheBlock(block)
-		if verr != nil {
-			utils.L.Debugf("%v", verr)
-			return BlockValidationError(verr)
+		// blocks are validated against the chain when committing in order,
+		// blocks from other peers and late responses of reassigned windows are ignored
+		if !SharedScheduler.Expects(b.Sender, block.ChainID(), block.Height) {
+			continue
 		}
 
 		b.blocks = append(b.blocks, block)
@@ -231,39 +296,74 @@ func (b *ResponseBlocks) Validate() *Error {
 
 // - Reactions
 func (b Info) React() []Behavior {
+	negotiateEncoding(b.Sender, b.Encodings)
+
 	var behaviors []Behavior
-	if b.Peers > 0 && viper.GetBool("peer.sync") {
+	if b.Peers > 0 && utils.GetBool("peer.sync") {
 		behaviors = append(behaviors, &RequestPeers{b.Peers})
 	}
+	// blocks are only requested from trusted peers
+	if IsTrusted(b.Sender) {
+		b.advertise()
+	} else {
+		deferInfo(&b)
+	}
+
+	var unknown []string
+	for k := range b.Chains {
+		if blockchain.LoadChain(k) == nil {
+			unknown = append(unknown, k)
+		}
+	}
+	if discoveryPolicy() != DiscoveryNone {
+		if request := newRequestChains(unknownChains(unknown)); request != nil {
+			behaviors = append(behaviors, request)
+		}
+	}
+	if len(b.NodeID) > 0 && len(b.Challenge) > 0 {
+		behaviors = append(behaviors, NewAuth(b.Challenge, b.NodeID))
+	}
+	return behaviors
+}
+
+// Hand chains of the peer to the scheduler
+func (b Info) advertise() {
 	for k, v := range b.Chains {
 		chain := blockchain.LoadChain(k)
 		if chain == nil {
 			continue
 		}
+		// light peers are only asked for blocks they hold payloads
+		if !b.FullNode {
+			from, ok := b.Retained[k]
+			if !ok {
+				from = v
+			}
+			SharedScheduler.Retain(b.Sender, k, from)
+		}
 		if chain.Count >= v {
 			continue
 		}
-		behaviors = append(behaviors, &RequestBlocks{k, chain.Count, v - 1})
+		SharedScheduler.Advertise(b.Sender, k, v)
 	}
-	return behaviors
 }
 
 // Split blocks for every 1 MB
 func (b RequestBlocks) React() []Behavior {
 	chain := blockchain.LoadChain(b.ChainID)
 
-	if !viper.GetBool("message.division") {
-		return []Behavior{&ResponseBlocks{blocks: chain.GetBlocks(b.From, b.To)}}
+	if !utils.GetBool("message.division") {
+		return []Behavior{&ResponseBlocks{blocks: chain.GetLocalBlocks(b.From, b.To)}}
 	}
 
 	var behaviors []Behavior
 	var blocks []*blockchain.Block
 	size := 0
-	maxsize := viper.GetInt("message.maxsize") * 1024 * 1024
+	maxsize := utils.GetInt("message.maxsize") * 1024 * 1024
 
 	for i := b.From; i <= b.To; i++ {
-		block := chain.GetBlock(i)
-		if block == nil {
+		block := chain.GetLocalBlock(i)
+		if block == nil || block.Pruned {
 			break
 		}
 
@@ -302,16 +402,30 @@ func (b ResponsePeers) React() []Behavior {
 	return nil
 }
 
+// Announce the last committed block of each chain to other peers
 func (b ResponseBlocks) React() []Behavior {
-	for _, v := range b.blocks {
-		blockchain.LoadChain(v.ChainID()).CommitCache()
+	for _, tip := range SharedScheduler.Receive(b.Sender, b.blocks) {
+		Announce(tip, b.Sender)
 	}
 	return nil
 }
 
+func (b ResponseBlocks) carriedBlocks() []*blockchain.Block {
+	return b.blocks
+}
+
+func (b *ResponseBlocks) receiveBlocks(blocks []*blockchain.Block) {
+	b.blocks = blocks
+}
+
 // Deserialize
 func DeserializeBehavior(msg *Message) (Behavior, error) {
 	instance := MapBehavior(msg.Type)
+	if receiver, ok := instance.(blockReceiver); ok && msg.Data == nil {
+		receiver.receiveBlocks(msg.blocks)
+		return instance, nil
+	}
+
 	err := json.Unmarshal(msg.Data, instance)
 	if err != nil {
 		return nil, err
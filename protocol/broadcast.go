@@ -4,15 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
 	"github.com/Infnote/infnotechain/utils"
 )
 
 // TODO: clear every 1 hour
 var broadcastKeys = map[string]bool{}
 
-// TODO: may need to find a better way to save the channel
-var BroadcastChannel = make(chan *BroadcastBlock)
-
+// Full block broadcast is still accepted from peers running older versions,
+// but blocks are relayed to others by announcements.
 type BroadcastBlock struct {
 	Block  json.RawMessage `json:"block"`
 	block  *blockchain.Block
@@ -41,12 +41,29 @@ func (b BroadcastBlock) Serialize() [] byte {
 	return data
 }
 
+func (b BroadcastBlock) carriedBlocks() []*blockchain.Block {
+	return []*blockchain.Block{b.block}
+}
+
+func (b *BroadcastBlock) receiveBlocks(blocks []*blockchain.Block) {
+	if len(blocks) > 0 {
+		b.block = blocks[0]
+	}
+}
+
 func (b *BroadcastBlock) Validate() *Error {
-	block, err := blockchain.DeserializeBlock(b.Block)
-	if err != nil {
-		return JSONDecodeError(err.Error())
+	// block may be decoded already from protobuf message
+	if b.block == nil {
+		block, err := blockchain.DeserializeBlock(b.Block)
+		if err != nil {
+			return JSONDecodeError(err.Error())
+		}
+		b.block = block
+	}
+
+	if err := checkTrusted(b.Sender); err != nil {
+		return err
 	}
-	b.block = block
 
 	if broadcastKeys[b.ID] {
 		return DuplicateBroadcastError(b.ID)
@@ -59,7 +76,8 @@ func (b *BroadcastBlock) Validate() *Error {
 
 	verr := chain.ValidateBlock(b.block)
 	if verr != nil {
-		utils.L.Debugf("%v", verr)
+		utils.L.With(utils.Fields{"chain_id": chain.ID, "height": b.block.Height, "msg_id": b.ID}).Debugf("%v", verr)
+		metrics.ValidationFailures.Inc(verr.Code())
 		return BlockValidationError(verr)
 	}
 
@@ -67,11 +85,10 @@ func (b *BroadcastBlock) Validate() *Error {
 }
 
 func (b *BroadcastBlock) React() []Behavior {
-	blockchain.LoadChain(b.block.ChainID()).SaveBlock(b.block)
+	if blockchain.LoadChain(b.block.ChainID()).SaveBlock(b.block) {
+		Announce(b.block, b.Sender)
+	}
 	broadcastKeys[b.ID] = true
-	go func() {
-		BroadcastChannel <- b
-	}()
 	return nil
 }
 
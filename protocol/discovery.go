@@ -0,0 +1,284 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/utils"
+	"sync"
+	"time"
+)
+
+// Chains unknown to us are learned by requesting their genesis blocks,
+// what to do with them is decided by 'discovery.policy':
+// none - ignore, list - keep as available chains, auto - subscribe
+const (
+	DiscoveryNone = "none"
+	DiscoveryList = "list"
+	DiscoveryAuto = "auto"
+)
+
+const maxRequestChains = 64
+
+// Behaviors sent to all connected peers
+var GossipChannel = make(chan Behavior)
+
+// Chain IDs requested with their expiry, responses of peers
+// slower than that are dropped like chains never requested
+const requestedChainsExpiry = 5 * time.Minute
+
+var requestedChains = map[string]time.Time{}
+var requestedLock sync.Mutex
+
+type RequestChains struct {
+	ChainIDs []string `json:"chain_ids"`
+}
+
+type ChainGenesis struct {
+	Genesis json.RawMessage `json:"genesis"`
+	Count   uint64          `json:"count"`
+}
+
+type ResponseChains struct {
+	Chains  []ChainGenesis `json:"chains"`
+	Sender  *network.Peer  `json:"-"`
+	genesis []*blockchain.Block
+	counts  []uint64
+}
+
+func discoveryPolicy() string {
+	return utils.GetString("discovery.policy")
+}
+
+// Remember chain IDs requested, responses of chains never requested are dropped
+func newRequestChains(ids []string) *RequestChains {
+	if len(ids) == 0 {
+		return nil
+	}
+	if len(ids) > maxRequestChains {
+		ids = ids[:maxRequestChains]
+	}
+
+	requestedLock.Lock()
+	now := time.Now()
+	expireRequestedChains(now)
+	for _, id := range ids {
+		requestedChains[id] = now.Add(requestedChainsExpiry)
+	}
+	requestedLock.Unlock()
+
+	return &RequestChains{ChainIDs: ids}
+}
+
+func isRequestedChain(id string, forget bool) bool {
+	requestedLock.Lock()
+	defer requestedLock.Unlock()
+
+	expiry, ok := requestedChains[id]
+	requested := ok && time.Now().Before(expiry)
+	if forget || (ok && !requested) {
+		delete(requestedChains, id)
+	}
+	return requested
+}
+
+// Requests never responded are removed, requestedLock must be held
+func expireRequestedChains(now time.Time) {
+	for id, expiry := range requestedChains {
+		if !now.Before(expiry) {
+			delete(requestedChains, id)
+		}
+	}
+}
+
+// Discovered chain of the ID, false if it cannot be read from the storage
+// which is logged here, so callers skip the chain instead of failing
+func getDiscoveredChain(id string) (*blockchain.DiscoveredChain, bool) {
+	discovered, err := blockchain.GetDiscoveredChain(id)
+	if err != nil {
+		utils.L.With(utils.Fields{"chain_id": id}).Warningf("failed to get discovered chain %v: %v", id, err)
+		return nil, false
+	}
+	return discovered, true
+}
+
+// Chain IDs neither subscribed nor discovered
+func unknownChains(ids []string) []string {
+	var unknown []string
+	for _, id := range ids {
+		if blockchain.LoadChain(id) != nil {
+			continue
+		}
+		if discovered, ok := getDiscoveredChain(id); ok && discovered == nil {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
+
+// Ask all peers about a chain, peers holding it respond
+// with its genesis and count, then missing blocks are downloaded
+func RequestChain(id string) {
+	Gossip(newRequestChains([]string{id}))
+}
+
+func Gossip(behavior Behavior) {
+	metrics.BroadcastQueue.Inc()
+	go func() {
+		GossipChannel <- behavior
+		metrics.BroadcastQueue.Dec()
+	}()
+}
+
+// Accept a chain with its genesis block
+func SubscribeChain(genesis *blockchain.Block) *blockchain.Chain {
+	id := genesis.ChainID()
+	if blockchain.LoadChain(id) == nil {
+		blockchain.NewReadonlyChain(id).Sync()
+	}
+
+	chain := blockchain.LoadChain(id)
+	if chain.Count == 0 {
+		chain.SaveBlock(genesis)
+	}
+	utils.L.Infof("chain subscribed: %v", id)
+	UpdateSubscription()
+	return chain
+}
+
+func (b RequestChains) Validate() *Error {
+	if len(b.ChainIDs) == 0 {
+		return BadRequestError("'chain_ids' is required")
+	}
+	if len(b.ChainIDs) > maxRequestChains {
+		return BadRequestError(fmt.Sprintf("request at most %v chains at a time", maxRequestChains))
+	}
+	return nil
+}
+
+// Respond genesis of chains we subscribed or discovered
+func (b RequestChains) React() []Behavior {
+	var chains []ChainGenesis
+	for _, id := range b.ChainIDs {
+		if chain := blockchain.LoadChain(id); chain != nil {
+			if genesis := chain.GetLocalBlock(0); genesis != nil {
+				chains = append(chains, ChainGenesis{genesis.Serialize(), chain.Count})
+			}
+			continue
+		}
+		if discovered, _ := getDiscoveredChain(id); discovered != nil {
+			chains = append(chains, ChainGenesis{discovered.Genesis.Serialize(), 0})
+		}
+	}
+
+	if len(chains) == 0 {
+		return nil
+	}
+	return []Behavior{&ResponseChains{Chains: chains}}
+}
+
+func (b *ResponseChains) Validate() *Error {
+	for _, v := range b.Chains {
+		genesis, err := blockchain.DeserializeBlock(v.Genesis)
+		if err != nil {
+			return JSONDecodeError(err.Error())
+		}
+
+		if genesis.Height != 0 || len(genesis.PrevHash) > 0 {
+			return BadRequestError("not a genesis block")
+		}
+
+		if err := genesis.Validate(); err != nil {
+			metrics.ValidationFailures.Inc(err.Code())
+			return BlockValidationError(err)
+		}
+
+		id := genesis.ChainID()
+		if blockchain.LoadChain(id) == nil && !isRequestedChain(id, false) {
+			discovered, ok := getDiscoveredChain(id)
+			if !ok {
+				continue
+			}
+			if discovered == nil {
+				utils.L.Debugf("chain not requested: %v", id)
+				continue
+			}
+		}
+
+		b.genesis = append(b.genesis, genesis)
+		b.counts = append(b.counts, v.Count)
+	}
+	return nil
+}
+
+func (b ResponseChains) React() []Behavior {
+	for i, genesis := range b.genesis {
+		id := genesis.ChainID()
+		isRequestedChain(id, true)
+
+		if chain := blockchain.LoadChain(id); chain != nil {
+			if chain.Count == 0 {
+				chain.SaveBlock(genesis)
+			}
+			if IsTrusted(b.Sender) {
+				SharedScheduler.Advertise(b.Sender, id, b.counts[i])
+			}
+			continue
+		}
+
+		if discoveryPolicy() == DiscoveryNone {
+			continue
+		}
+
+		known, ok := getDiscoveredChain(id)
+		if !ok {
+			continue
+		}
+		discovered := &blockchain.DiscoveredChain{
+			ID:      id,
+			Genesis: genesis,
+			Count:   b.counts[i],
+			Last:    time.Now(),
+		}
+		if err := discovered.Save(); err != nil {
+			utils.L.With(utils.Fields{"chain_id": id}).Warningf("failed to save discovered chain %v: %v", id, err)
+			continue
+		}
+
+		// gossip a newly discovered chain to others
+		if known == nil {
+			utils.L.Infof("chain discovered: %v", id)
+			Announce(genesis, b.Sender)
+		}
+
+		if discoveryPolicy() == DiscoveryAuto {
+			SubscribeChain(genesis)
+			if IsTrusted(b.Sender) {
+				SharedScheduler.Advertise(b.Sender, id, b.counts[i])
+			}
+		}
+	}
+	return nil
+}
+
+func (b RequestChains) String() string {
+	var result string
+	result += fmt.Sprintf("====== RequestChains =====\n")
+	result += fmt.Sprintf("[Chain IDs]\n")
+	for _, id := range b.ChainIDs {
+		result += fmt.Sprintf("\t%v\n", id)
+	}
+	return result
+}
+
+func (b ResponseChains) String() string {
+	var result string
+	result += fmt.Sprintf("===== ResponseChains =====\n")
+	result += fmt.Sprintf("[Chains]\n")
+	for i, genesis := range b.genesis {
+		result += fmt.Sprintf("\t[%v] %v\n", genesis.ChainID(), b.counts[i])
+	}
+	return result
+}
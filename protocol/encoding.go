@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/protocol/codegen"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/golang/protobuf/proto"
+)
+
+// Every peer understands JSON, protobuf is used only when both sides
+// listed it in "encodings" of Info.
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// Behaviors carrying blocks are encoded as binary blocks in protobuf
+type blockCarrier interface {
+	carriedBlocks() []*blockchain.Block
+}
+
+type blockReceiver interface {
+	receiveBlocks(blocks []*blockchain.Block)
+}
+
+func supportedEncodings() []string {
+	if utils.GetString("message.encoding") == EncodingProtobuf {
+		return []string{EncodingJSON, EncodingProtobuf}
+	}
+	return []string{EncodingJSON}
+}
+
+// Choose the encoding for the peer from encodings it supports
+func negotiateEncoding(peer *network.Peer, encodings []string) {
+	if peer == nil {
+		return
+	}
+
+	peer.Encoding = EncodingJSON
+	for _, local := range supportedEncodings() {
+		for _, remote := range encodings {
+			if local == EncodingProtobuf && remote == EncodingProtobuf {
+				peer.Encoding = EncodingProtobuf
+			}
+		}
+	}
+}
+
+// JSON messages are always objects, any data not starting with '{'
+// is treated as protobuf
+func isJSONData(data []byte) bool {
+	return len(data) > 0 && data[0] == '{'
+}
+
+// Serialize message with the encoding negotiated with the peer
+func (m Message) SerializeFor(peer *network.Peer) []byte {
+	metrics.MessagesSent.Inc(m.Type)
+	if peer != nil && peer.Encoding == EncodingProtobuf {
+		return m.SerializeProtobuf()
+	}
+	return m.Serialize()
+}
+
+func (m Message) SerializeProtobuf() []byte {
+	msg := &wire.Message{Id: m.ID, Type: m.Type, Data: m.Data}
+	if m.blocks != nil {
+		msg.Data = nil
+		for _, block := range m.blocks {
+			msg.Blocks = append(msg.Blocks, &wire.Block{
+				Height:    block.Height,
+				Time:      block.Time,
+				PrevHash:  block.PrevHash,
+				Hash:      block.Hash,
+				Signature: block.Signature,
+				Payload:   block.Payload,
+			})
+		}
+	} else if msg.Data == nil && m.behavior != nil {
+		msg.Data = serializeBehavior(m.behavior)
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	return data
+}
+
+func DeserializeProtobufMessage(data []byte) (*Message, error) {
+	msg := &wire.Message{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	result := &Message{ID: msg.Id, Type: msg.Type, Data: msg.Data}
+	for _, block := range msg.Blocks {
+		result.blocks = append(result.blocks, &blockchain.Block{
+			Height:    block.Height,
+			Time:      block.Time,
+			PrevHash:  block.PrevHash,
+			Hash:      block.Hash,
+			Signature: block.Signature,
+			Payload:   block.Payload,
+		})
+	}
+	return result, nil
+}
@@ -37,6 +37,10 @@ func IncompatibleProtocolVersionError(err string) *Error {
 	return &Error{"IncompatibleProtocolVersionError", err}
 }
 
+func IncompatibleNetworkError(err string) *Error {
+	return &Error{"IncompatibleNetworkError", err}
+}
+
 func BadRequestError(err string) *Error {
 	return &Error{"BadRequestError", err}
 }
@@ -53,6 +57,10 @@ func BlockValidationError(err blockchain.BlockValidationError) *Error {
 	return &Error{"BlockValidationError", err.Code()}
 }
 
+func BlockNotFoundError(err string) *Error {
+	return &Error{"BlockNotFoundError", err}
+}
+
 func InvalidURLError(err string) *Error {
 	return &Error{"InvalidURLError", err}
 }
@@ -60,3 +68,7 @@ func InvalidURLError(err string) *Error {
 func DuplicateBroadcastError(err string) *Error {
 	return &Error{"DuplicateBroadcastError", err}
 }
+
+func UnauthorizedError(err string) *Error {
+	return &Error{"UnauthorizedError", err}
+}
@@ -1,52 +1,87 @@
 package protocol
 
 import (
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/utils"
 )
 
-func serialize(behaviors ...Behavior) [][]byte {
+func serialize(peer *network.Peer, behaviors ...Behavior) [][]byte {
 	var result [][]byte
 	for _, v := range behaviors {
-		utils.L.Debugf("made behavior:\n%v", v)
-		result = append(result, NewMessage(v).Serialize())
+		peerLogger(peer).With(utils.Fields{"msg_type": MapType(v)}).Debugf("made behavior:\n%v", v)
+		result = append(result, NewMessage(v).SerializeFor(peer))
 	}
 	return result
 }
 
-func HandleJSONData(sender interface{}, data []byte) [][]byte {
-	msg, err := DeserializeMessage(data)
+// Handle a JSON text message or a protobuf binary message
+func HandleData(sender interface{}, data []byte) [][]byte {
+	peer, _ := sender.(*network.Peer)
+	logger := peerLogger(peer)
+
+	var msg *Message
+	var err error
+	if isJSONData(data) {
+		msg, err = DeserializeMessage(data)
+	} else {
+		msg, err = DeserializeProtobufMessage(data)
+	}
 	if err != nil {
-		utils.L.Debugf("%v: %v", err, string(data))
-		return serialize(InvalidMessageError("invalid format of message"))
+		logger.Debugf("%v: %v", err, string(data))
+		return serialize(peer, InvalidMessageError("invalid format of message"))
 	}
 
+	logger = logger.With(utils.Fields{"msg_type": msg.Type, "msg_id": msg.ID})
 	behavior := MapBehavior(msg.Type)
 	if behavior == nil {
-		utils.L.Debugf("invalid message type: %v", msg.Type)
-		return serialize(InvalidMessageError("invalid type of message"))
+		logger.Debugf("invalid message type: %v", msg.Type)
+		return serialize(peer, InvalidMessageError("invalid type of message"))
 	}
+	metrics.MessagesReceived.Inc(msg.Type)
 
 	behavior, err = DeserializeBehavior(msg)
 	if err != nil {
-		utils.L.Debugf("%v: %+v", err, string(msg.Data))
-		return serialize(InvalidBehaviorError("invalid format of message data"))
+		logger.Debugf("%v: %+v", err, string(msg.Data))
+		return serialize(peer, InvalidBehaviorError("invalid format of message data"))
 	}
 
-	b, ok := behavior.(*BroadcastBlock)
-	if ok {
+	switch b := behavior.(type) {
+	case *BroadcastBlock:
 		b.ID = msg.ID
 		b.Sender = sender
+	case *ResponseBlocks:
+		b.Sender = peer
+	case *AnnounceBlock:
+		b.Sender = sender
+	case *Info:
+		b.Sender = peer
+	case *Auth:
+		b.Sender = peer
+	case *ResponseChains:
+		b.Sender = peer
+	case *Subscribe:
+		b.Sender = peer
 	}
 
 	rerr := behavior.Validate()
 	if rerr != nil {
-		return serialize(rerr)
+		logger.Debugf("invalid behavior: %v", rerr.Code)
+		return serialize(peer, rerr)
 	}
 
 	responses := behavior.React()
 	if len(responses) > 0 {
-		return serialize(responses...)
+		return serialize(peer, responses...)
 	}
 
 	return nil
 }
+
+// Logger with the address of peer, senders other than peers are not logged
+func peerLogger(peer *network.Peer) *utils.Logger {
+	if peer == nil {
+		return utils.L
+	}
+	return utils.L.With(utils.Fields{"peer": peer.Addr})
+}
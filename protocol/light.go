@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/utils"
+	"sync"
+	"time"
+)
+
+// Pruned payloads of light node are fetched from peers holding them,
+// fetched blocks are returned to the caller without saving.
+var fetches = map[string]chan *blockchain.Block{}
+var fetchesLock sync.Mutex
+
+const maxFetchPeers = 3
+
+func init() {
+	blockchain.PayloadFetcher = FetchBlock
+}
+
+func fetchKey(chainID string, height uint64) string {
+	return fmt.Sprintf("%v:%v", chainID, height)
+}
+
+// Peers are asked one by one until the context is done
+func FetchBlock(ctx context.Context, chainID string, height uint64) *blockchain.Block {
+	chain := blockchain.LoadChain(chainID)
+	if chain == nil {
+		return nil
+	}
+	header := chain.GetLocalBlock(height)
+	if header == nil {
+		return nil
+	}
+
+	key := fetchKey(chainID, height)
+	result := make(chan *blockchain.Block, 1)
+	fetchesLock.Lock()
+	fetches[key] = result
+	fetchesLock.Unlock()
+
+	defer func() {
+		fetchesLock.Lock()
+		delete(fetches, key)
+		fetchesLock.Unlock()
+	}()
+
+	peers := SharedScheduler.Holders(chainID, height)
+	if len(peers) > maxFetchPeers {
+		peers = peers[:maxFetchPeers]
+	}
+
+	for _, peer := range peers {
+		if !IsTrusted(peer) {
+			continue
+		}
+		send([]scheduledRequest{{peer, &RequestBlocks{chainID, height, height}}})
+
+		timer := time.NewTimer(windowTimeout())
+		select {
+		case block := <-result:
+			timer.Stop()
+			if block.Hash == header.Hash {
+				return block
+			}
+			utils.L.Warningf("fetched block %v mismatches local header", key)
+		case <-ctx.Done():
+			timer.Stop()
+			utils.L.Debugf("fetch block %v is canceled", key)
+			return nil
+		case <-timer.C:
+			utils.L.Debugf("fetch block %v from %v timeout", key, peer.Addr)
+		}
+	}
+
+	utils.L.Warningf("failed to fetch payload of block %v", key)
+	return nil
+}
+
+// Hand a validated block to the fetching caller
+func deliverFetched(block *blockchain.Block) bool {
+	fetchesLock.Lock()
+	defer fetchesLock.Unlock()
+
+	result, ok := fetches[fetchKey(block.ChainID(), block.Height)]
+	if !ok {
+		result, ok = fetches[block.Hash]
+	}
+	if !ok {
+		return false
+	}
+
+	select {
+	case result <- block:
+	default:
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/utils"
+	"time"
+)
+
+// Request a block by hash without knowing its chain,
+// responded by "response:blocks" with the block if the peer holds its payload
+type RequestBlock struct {
+	Hash string `json:"hash"`
+}
+
+func (b RequestBlock) Validate() *Error {
+	if len(b.Hash) == 0 {
+		return BadRequestError("'hash' is required")
+	}
+	return nil
+}
+
+func (b RequestBlock) React() []Behavior {
+	_, block := blockchain.SharedStorage().FindBlock(b.Hash)
+	if block == nil || block.Pruned {
+		return []Behavior{BlockNotFoundError(b.Hash)}
+	}
+	return []Behavior{&ResponseBlocks{blocks: []*blockchain.Block{block}}}
+}
+
+func (b RequestBlock) String() string {
+	var result string
+	result += fmt.Sprintf("====== RequestBlock ======\n")
+	result += fmt.Sprintf("[Hash] %v\n", b.Hash)
+	return result
+}
+
+// Remote lookups are made inside request handlers, so only a few
+// peers are asked and each of them has a short time to respond
+const (
+	maxLookupPeers = 2
+	lookupTimeout  = 3 * time.Second
+)
+
+// Ask peers one by one for a block by hash until the context is done,
+// fetches are keyed by hash in the same pending map of light node fetches
+func FetchBlockByHash(ctx context.Context, hash string, peers []*network.Peer) *blockchain.Block {
+	result := make(chan *blockchain.Block, 1)
+	fetchesLock.Lock()
+	fetches[hash] = result
+	fetchesLock.Unlock()
+
+	defer func() {
+		fetchesLock.Lock()
+		delete(fetches, hash)
+		fetchesLock.Unlock()
+	}()
+
+	asked := 0
+	for _, peer := range peers {
+		if asked >= maxLookupPeers {
+			break
+		}
+		if !IsTrusted(peer) {
+			continue
+		}
+		asked++
+		send([]scheduledRequest{{peer, &RequestBlock{hash}}})
+
+		timer := time.NewTimer(lookupTimeout)
+		select {
+		case block := <-result:
+			timer.Stop()
+			return block
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			utils.L.Debugf("fetch block %v from %v timeout", hash, peer.Addr)
+		}
+	}
+	return nil
+}
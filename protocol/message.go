@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"encoding/json"
+	"github.com/Infnote/infnotechain/blockchain"
 	"github.com/Infnote/infnotechain/utils"
 	"github.com/mr-tron/base58"
 	"math/rand"
@@ -19,16 +20,29 @@ type Message struct {
 	ID   string          `json:"id"`
 	Type string          `json:"type"`
 	Data json.RawMessage `json:"data"`
+
+	// Blocks are carried as binary in protobuf encoding,
+	// JSON data of these behaviors is only made when needed.
+	blocks   []*blockchain.Block
+	behavior Behavior
 }
 
+const ProtocolVersion = "1.1"
+
 var MessageTypeMap = map[string]reflect.Type{
 	"info":            reflect.TypeOf(Info{}),
 	"error":           reflect.TypeOf(Error{}),
 	"request:blocks":  reflect.TypeOf(RequestBlocks{}),
+	"request:block":   reflect.TypeOf(RequestBlock{}),
 	"request:peers":   reflect.TypeOf(RequestPeers{}),
 	"response:blocks": reflect.TypeOf(ResponseBlocks{}),
 	"response:peers":  reflect.TypeOf(ResponsePeers{}),
 	"broadcast:block": reflect.TypeOf(BroadcastBlock{}),
+	"announce:block":  reflect.TypeOf(AnnounceBlock{}),
+	"auth":            reflect.TypeOf(Auth{}),
+	"request:chains":  reflect.TypeOf(RequestChains{}),
+	"response:chains": reflect.TypeOf(ResponseChains{}),
+	"subscribe":       reflect.TypeOf(Subscribe{}),
 }
 
 var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
@@ -55,7 +69,7 @@ func MapType(i Behavior) string {
 	return strings.ToLower(name)
 }
 
-func NewMessage(data Behavior) *Message {
+func serializeBehavior(data Behavior) json.RawMessage {
 	var d []byte
 	var err error
 	switch s := data.(type) {
@@ -70,14 +84,25 @@ func NewMessage(data Behavior) *Message {
 			utils.L.Fatal(err)
 		}
 	}
+	return json.RawMessage(d)
+}
 
+func NewMessage(data Behavior) *Message {
 	id := make([]byte, 8)
 	for i := range id {
 		id[i] = byte(rand.Intn(90) + 32)
 	}
 
+	msg := &Message{ID: base58.Encode(id), Type: MapType(data)}
+	if carrier, ok := data.(blockCarrier); ok {
+		msg.blocks = carrier.carriedBlocks()
+		msg.behavior = data
+		return msg
+	}
+
 	// Convert nested struct need to precompute the nest value first
-	return &Message{base58.Encode(id), MapType(data), json.RawMessage(d)}
+	msg.Data = serializeBehavior(data)
+	return msg
 }
 
 func DeserializeMessage(jsonData []byte) (*Message, error) {
@@ -90,6 +115,10 @@ func DeserializeMessage(jsonData []byte) (*Message, error) {
 }
 
 func (m Message) Serialize() []byte {
+	if m.Data == nil && m.behavior != nil {
+		m.Data = serializeBehavior(m.behavior)
+	}
+
 	data, err := json.Marshal(m)
 
 	if err != nil {
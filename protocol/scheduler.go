@@ -0,0 +1,386 @@
+package protocol
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Missing blocks of a chain are split into windows of heights,
+// windows are requested from every peer advertised the chain in parallel
+// and blocks are committed in order no matter which window arrives first.
+type DownloadScheduler struct {
+	chains map[string]*chainDownload
+	lock   sync.Mutex
+}
+
+type downloadWindow struct {
+	From     uint64
+	To       uint64
+	Peer     *network.Peer
+	Deadline time.Time
+	last     *network.Peer
+}
+
+type chainDownload struct {
+	peers   map[*network.Peer]uint64
+	lowest  map[*network.Peer]uint64
+	windows []*downloadWindow
+	blocks  map[uint64]*blockchain.Block
+	next    uint64
+	target  uint64
+}
+
+type scheduledRequest struct {
+	peer     *network.Peer
+	behavior Behavior
+}
+
+var SharedScheduler = NewDownloadScheduler()
+
+func NewDownloadScheduler() *DownloadScheduler {
+	return &DownloadScheduler{chains: map[string]*chainDownload{}}
+}
+
+func windowSize() uint64 {
+	size := utils.GetInt("download.window")
+	if size <= 0 {
+		return 1
+	}
+	return uint64(size)
+}
+
+func windowTimeout() time.Duration {
+	return time.Duration(utils.GetInt("download.timeout")) * time.Second
+}
+
+// Record the count of blocks a peer has on a chain and request missing blocks
+func (s *DownloadScheduler) Advertise(peer *network.Peer, chainID string, count uint64) {
+	if peer == nil {
+		return
+	}
+
+	chain := blockchain.LoadChain(chainID)
+	if chain == nil {
+		return
+	}
+
+	s.lock.Lock()
+	d := s.download(chainID)
+	if count > d.peers[peer] {
+		d.peers[peer] = count
+	}
+	if count > d.target {
+		d.target = count
+	}
+	requests := d.schedule(chain)
+	s.lock.Unlock()
+
+	send(requests)
+}
+
+func (s *DownloadScheduler) download(chainID string) *chainDownload {
+	d := s.chains[chainID]
+	if d == nil {
+		d = &chainDownload{
+			peers:  map[*network.Peer]uint64{},
+			lowest: map[*network.Peer]uint64{},
+			blocks: map[uint64]*blockchain.Block{},
+		}
+		s.chains[chainID] = d
+	}
+	return d
+}
+
+// Record the lowest height of blocks a light peer holds payloads
+func (s *DownloadScheduler) Retain(peer *network.Peer, chainID string, from uint64) {
+	if peer == nil {
+		return
+	}
+
+	s.lock.Lock()
+	s.download(chainID).lowest[peer] = from
+	s.lock.Unlock()
+}
+
+// Peers holding the block with payload, full nodes come first
+func (s *DownloadScheduler) Holders(chainID string, height uint64) []*network.Peer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.chains[chainID]
+	if d == nil {
+		return nil
+	}
+
+	var peers []*network.Peer
+	for peer, count := range d.peers {
+		if count > height && d.lowest[peer] <= height {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return d.lowest[peers[i]] < d.lowest[peers[j]]
+	})
+	return peers
+}
+
+// Highest count of blocks advertised by connected peers on a chain
+func (s *DownloadScheduler) BestKnown(chainID string) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var best uint64
+	if d := s.chains[chainID]; d != nil {
+		for _, count := range d.peers {
+			if count > best {
+				best = count
+			}
+		}
+	}
+	return best
+}
+
+// Forget a disconnected peer and hand its windows to others
+func (s *DownloadScheduler) RemovePeer(peer *network.Peer) {
+	var requests []scheduledRequest
+
+	s.lock.Lock()
+	for id, d := range s.chains {
+		delete(d.peers, peer)
+		delete(d.lowest, peer)
+		for _, w := range d.windows {
+			if w.Peer == peer {
+				w.Peer = nil
+			}
+		}
+		if chain := blockchain.LoadChain(id); chain != nil {
+			requests = append(requests, d.schedule(chain)...)
+		} else {
+			delete(s.chains, id)
+		}
+	}
+	s.lock.Unlock()
+
+	send(requests)
+}
+
+// Whether a block is expected from the peer by its window of the chain
+func (s *DownloadScheduler) Expects(peer *network.Peer, chainID string, height uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.expects(peer, chainID, height)
+}
+
+func (s *DownloadScheduler) expects(peer *network.Peer, chainID string, height uint64) bool {
+	d := s.chains[chainID]
+	if d == nil {
+		return false
+	}
+	w := d.window(height)
+	return w != nil && w.Peer != nil && w.Peer == peer
+}
+
+// Accept blocks downloaded from the peer assigned to their windows,
+// commit whatever could be committed in order and return the last
+// committed block of each chain
+func (s *DownloadScheduler) Receive(peer *network.Peer, blocks []*blockchain.Block) []*blockchain.Block {
+	var requests []scheduledRequest
+	var tips []*blockchain.Block
+
+	s.lock.Lock()
+	chains := map[string]*blockchain.Chain{}
+	for _, block := range blocks {
+		if !s.expects(peer, block.ChainID(), block.Height) {
+			continue
+		}
+		// chain may be deleted while downloading
+		chain := blockchain.LoadChain(block.ChainID())
+		if chain == nil {
+			delete(s.chains, block.ChainID())
+			continue
+		}
+		s.chains[block.ChainID()].blocks[block.Height] = block
+		chains[block.ChainID()] = chain
+	}
+	for id, chain := range chains {
+		d := s.chains[id]
+		if tip := d.commit(chain); tip != nil {
+			tips = append(tips, tip)
+		}
+		requests = append(requests, d.schedule(chain)...)
+	}
+	s.lock.Unlock()
+
+	send(requests)
+	return tips
+}
+
+// Reassign windows which are not responded in time
+func (s *DownloadScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		var requests []scheduledRequest
+
+		s.lock.Lock()
+		now := time.Now()
+		for id, d := range s.chains {
+			chain := blockchain.LoadChain(id)
+			if chain == nil {
+				delete(s.chains, id)
+				continue
+			}
+			for _, w := range d.windows {
+				if _, ok := d.missing(w, chain); ok && w.Peer != nil && now.After(w.Deadline) {
+					utils.L.With(utils.Fields{"chain_id": id, "peer": w.Peer.Addr}).
+						Debugf("window [%v, %v] of chain %v timeout", w.From, w.To, id)
+					w.last = w.Peer
+					w.Peer = nil
+				}
+			}
+			requests = append(requests, d.schedule(chain)...)
+		}
+		s.lock.Unlock()
+
+		send(requests)
+	}
+}
+
+// Returns the first height of the window which is neither committed nor received
+func (d *chainDownload) missing(w *downloadWindow, chain *blockchain.Chain) (uint64, bool) {
+	for h := w.From; h <= w.To; h++ {
+		if h >= chain.Count && d.blocks[h] == nil {
+			return h, true
+		}
+	}
+	return 0, false
+}
+
+func (d *chainDownload) window(height uint64) *downloadWindow {
+	for _, w := range d.windows {
+		if w.From <= height && height <= w.To {
+			return w
+		}
+	}
+	return nil
+}
+
+// Validate and commit continuous blocks from current count of the chain,
+// a window containing an invalid block will be requested again
+func (d *chainDownload) commit(chain *blockchain.Chain) *blockchain.Block {
+	var tip *blockchain.Block
+	for {
+		block := d.blocks[chain.Count]
+		if block == nil {
+			break
+		}
+		delete(d.blocks, block.Height)
+
+		if err := chain.CacheBlock(block); err != nil {
+			utils.L.With(utils.Fields{"chain_id": chain.ID, "height": block.Height}).Debugf("%v", err)
+			if w := d.window(block.Height); w != nil {
+				for h := w.From; h <= w.To; h++ {
+					delete(d.blocks, h)
+				}
+				w.last = w.Peer
+				w.Peer = nil
+			}
+			break
+		}
+		chain.CommitCache()
+		tip = block
+	}
+	return tip
+}
+
+// Drop finished windows, create windows for heights not requested yet
+// and assign idle windows to peers with the least load
+func (d *chainDownload) schedule(chain *blockchain.Chain) []scheduledRequest {
+	var windows []*downloadWindow
+	for _, w := range d.windows {
+		if w.To >= chain.Count {
+			windows = append(windows, w)
+		}
+	}
+	d.windows = windows
+
+	if d.next < chain.Count {
+		d.next = chain.Count
+	}
+
+	limit := utils.GetInt("download.parallel") * len(d.peers)
+	for d.next < d.target && len(d.windows) < limit {
+		to := d.next + windowSize() - 1
+		if to > d.target-1 {
+			to = d.target - 1
+		}
+		d.windows = append(d.windows, &downloadWindow{From: d.next, To: to})
+		d.next = to + 1
+	}
+
+	load := map[*network.Peer]int{}
+	for _, w := range d.windows {
+		if _, ok := d.missing(w, chain); ok && w.Peer != nil {
+			load[w.Peer]++
+		}
+	}
+
+	var requests []scheduledRequest
+	for _, w := range d.windows {
+		from, ok := d.missing(w, chain)
+		if !ok || w.Peer != nil {
+			continue
+		}
+
+		var candidates []*network.Peer
+		for peer, count := range d.peers {
+			if count > w.To && d.lowest[peer] <= w.From && load[peer] < utils.GetInt("download.parallel") {
+				candidates = append(candidates, peer)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if (a == w.last) != (b == w.last) {
+				return b == w.last
+			}
+			return load[a] < load[b]
+		})
+
+		w.Peer = candidates[0]
+		w.Deadline = time.Now().Add(windowTimeout())
+		load[w.Peer]++
+		requests = append(requests, scheduledRequest{w.Peer, &RequestBlocks{chain.ID, from, w.To}})
+	}
+	return requests
+}
+
+// Requests are small, a peer not taking one in time is busy or gone
+// and its window is reassigned when timeout
+const requestWait = time.Second
+
+func send(requests []scheduledRequest) {
+	for _, r := range requests {
+		logger := utils.L.With(utils.Fields{"peer": r.peer.Addr})
+		logger.Debugf("request blocks from %v:\n%v", r.peer.Addr, r.behavior)
+		if !r.peer.Write(NewMessage(r.behavior).SerializeFor(r.peer), requestWait) {
+			logger.Debugf("request to %v is dropped", r.peer.Addr)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/network"
+)
+
+// Chains a peer wants to receive announcements of, sent after Info and
+// every time local chains changed. Peers never subscribed receive all.
+type Subscribe struct {
+	ChainIDs  []string      `json:"chain_ids"`
+	Discovery bool          `json:"discovery"`
+	Sender    *network.Peer `json:"-"`
+}
+
+func NewSubscribe() *Subscribe {
+	ids := []string{}
+	for _, chain := range blockchain.LoadAllChains() {
+		ids = append(ids, chain.ID)
+	}
+	return &Subscribe{
+		ChainIDs:  ids,
+		Discovery: discoveryPolicy() != DiscoveryNone,
+	}
+}
+
+// Send current subscription to all peers
+func UpdateSubscription() {
+	Gossip(NewSubscribe())
+}
+
+// Whether the peer is interested in a block, genesis blocks
+// of unknown chains are for discovery
+func IsSubscribed(peer *network.Peer, chainID string, height uint64) bool {
+	subscriptions, discovery := peer.Subscriptions()
+	if subscriptions == nil {
+		return true
+	}
+	return subscriptions[chainID] || (height == 0 && discovery)
+}
+
+func (b Subscribe) Validate() *Error {
+	if b.Sender == nil {
+		return BadRequestError("unknown peer")
+	}
+	return nil
+}
+
+func (b Subscribe) React() []Behavior {
+	subscriptions := map[string]bool{}
+	for _, id := range b.ChainIDs {
+		subscriptions[id] = true
+	}
+	b.Sender.Subscribe(subscriptions, b.Discovery)
+	return nil
+}
+
+func (b Subscribe) String() string {
+	var result string
+	result += fmt.Sprintf("======== Subscribe =======\n")
+	result += fmt.Sprintf("[Discovery] %v\n", b.Discovery)
+	result += fmt.Sprintf("[Chain IDs]\n")
+	for _, id := range b.ChainIDs {
+		result += fmt.Sprintf("\t%v\n", id)
+	}
+	return result
+}
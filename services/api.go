@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HTTP JSON API for applications at /api/v1, shares the port with block stream
+//
+//	GET  /api/v1/chains
+//	GET  /api/v1/chains/[chain id]
+//	GET  /api/v1/chains/[chain id]/blocks?from=&limit=
+//	POST /api/v1/chains/[chain id]/blocks       {"payload": base64}
+//	GET  /api/v1/chains/[chain id]/blocks/[height or hash]
+//	GET  /api/v1/blocks/[hash]
+//	GET  /api/v1/peers?offset=&limit=
+//	POST /api/v1/peers                          {"addr": ...}
+const apiPrefix = "/api/v1/"
+
+const defaultPageSize = 20
+const maxPageSize = 100
+
+// Largest request bodies, payloads of blocks are encoded in base64
+const maxBlockRequestSize = (MaxPayloadSize+2)/3*4 + 1024
+const maxPeerRequestSize = 4 * 1024
+
+type apiChain struct {
+	Ref   int64  `json:"ref"`
+	ID    string `json:"id"`
+	Count uint64 `json:"count"`
+	Owner bool   `json:"owner"`
+}
+
+type apiPeer struct {
+	Addr   string `json:"addr"`
+	Rank   int    `json:"rank"`
+	Last   int64  `json:"last"`
+	Server bool   `json:"server"`
+	Online bool   `json:"online"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newAPIChain(chain *blockchain.Chain) apiChain {
+	return apiChain{chain.Ref, chain.ID, chain.Count, chain.IsOwner()}
+}
+
+func httpStatus(kind ErrorKind) int {
+	switch kind {
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case Internal:
+		return http.StatusInternalServerError
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if e, ok := err.(*ManageError); ok {
+		writeJSON(w, httpStatus(e.Kind), map[string]apiError{"error": {e.Kind.String(), e.Message}})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]apiError{"error": {"Internal", err.Error()}})
+}
+
+// Decode a JSON request body of at most limit bytes, the error
+// is written and false is returned if it is too large or invalid
+func decodeBody(w http.ResponseWriter, r *http.Request, limit int64, value interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(value)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		message := fmt.Sprintf("request body is larger than %v bytes", limit)
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]apiError{"error": {InvalidArgument.String(), message}})
+		return false
+	}
+	if err != nil {
+		writeError(w, manageError(InvalidArgument, "invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// Unsigned integer query with default value
+func queryUint(r *http.Request, key string, value uint64) (uint64, error) {
+	v := r.URL.Query().Get(key)
+	if len(v) == 0 {
+		return value, nil
+	}
+	result, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, manageError(InvalidArgument, "invalid '%v': %v", key, v)
+	}
+	return result, nil
+}
+
+// Boolean query, false if it is not set
+func queryBool(r *http.Request, key string) (bool, error) {
+	v := r.URL.Query().Get(key)
+	if len(v) == 0 {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, manageError(InvalidArgument, "invalid '%v': %v", key, v)
+	}
+	return result, nil
+}
+
+func pageSize(r *http.Request) (uint64, error) {
+	limit, err := queryUint(r, "limit", defaultPageSize)
+	if err != nil {
+		return 0, err
+	}
+	if limit == 0 || limit > maxPageSize {
+		return 0, manageError(InvalidArgument, "'limit' should be between 1 and %v", maxPageSize)
+	}
+	return limit, nil
+}
+
+func serializeBlocks(blocks []*blockchain.Block) []json.RawMessage {
+	result := []json.RawMessage{}
+	for _, block := range blocks {
+		result = append(result, block.Serialize())
+	}
+	return result
+}
+
+func handleAPIChains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	chains := []apiChain{}
+	for _, chain := range blockchain.LoadAllChains() {
+		chains = append(chains, newAPIChain(chain))
+	}
+	writeJSON(w, http.StatusOK, map[string][]apiChain{"chains": chains})
+}
+
+func handleAPIChain(w http.ResponseWriter, r *http.Request, chainID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	chain, err := GetChain(chainID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIChain(chain))
+}
+
+// Blocks from height 'from', 'next' is null when reached the end
+func handleAPIBlocks(w http.ResponseWriter, r *http.Request, chainID string) {
+	switch r.Method {
+	case http.MethodGet:
+		chain, err := GetChain(chainID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		from, err := queryUint(r, "from", 0)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		limit, err := pageSize(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var blocks []*blockchain.Block
+		var next *uint64
+		if from < chain.Count {
+			to := from + limit - 1
+			if to >= chain.Count-1 {
+				to = chain.Count - 1
+			} else {
+				n := to + 1
+				next = &n
+			}
+			if blocks, err = GetBlocks(r.Context(), chainID, from, to); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"chain_id": chainID,
+			"count":    chain.Count,
+			"blocks":   serializeBlocks(blocks),
+			"next":     next,
+		})
+	case http.MethodPost:
+		request := &struct {
+			Payload []byte `json:"payload"`
+		}{}
+		if !decodeBody(w, r, maxBlockRequestSize, request) {
+			return
+		}
+
+		block, err := CreateBlock(chainID, request.Payload)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("%vchains/%v/blocks/%v", apiPrefix, chainID, block.Height))
+		writeJSON(w, http.StatusCreated, json.RawMessage(block.Serialize()))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// Block is looked up by height if the key is a number, otherwise by hash
+func handleAPIBlock(w http.ResponseWriter, r *http.Request, chainID string, key string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var block *blockchain.Block
+	var err error
+	if height, e := strconv.ParseUint(key, 10, 64); e == nil {
+		block, err = GetBlock(r.Context(), chainID, height)
+	} else {
+		var remote bool
+		if remote, err = queryBool(r, "remote"); err == nil {
+			block, err = GetBlockByHash(r.Context(), chainID, key, remote)
+		}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, json.RawMessage(block.Serialize()))
+}
+
+// Block of any chain by hash, chain ID is included in the response
+func handleAPIBlockByHash(w http.ResponseWriter, r *http.Request, hash string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	remote, err := queryBool(r, "remote")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	block, err := GetBlockByHash(r.Context(), "", hash, remote)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, streamEvent{block.ChainID(), block.Serialize()})
+}
+
+func handleAPIPeers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		offset, err := queryUint(r, "offset", 0)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		limit, err := pageSize(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		peers := []apiPeer{}
+		all := GetPeers(0)
+		for i := offset; i < uint64(len(all)) && i < offset+limit; i++ {
+			p := all[i]
+			peers = append(peers, apiPeer{p.Addr, p.Rank, p.Last.Unix(), p.IsServer, p.Online})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"total": len(all),
+			"peers": peers,
+		})
+	case http.MethodPost:
+		request := &struct {
+			Addr string `json:"addr"`
+		}{}
+		if !decodeBody(w, r, maxPeerRequestSize, request) {
+			return
+		}
+		addr, err := AddPeer(request.Addr)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]string{"addr": addr})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// Token in header 'Authorization: Bearer [token]' or 'X-API-Key',
+// browsers can only pass it by query 'token' for block stream
+func requestToken(r *http.Request) string {
+	if t := r.Header.Get("Authorization"); len(t) > 0 {
+		return t
+	}
+	if t := r.Header.Get("X-API-Key"); len(t) > 0 {
+		return t
+	}
+	return r.URL.Query().Get("token")
+}
+
+// Reading requires read scope, others require admin scope
+func authorized(w http.ResponseWriter, r *http.Request) bool {
+	scope := ScopeAdmin
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		scope = ScopeRead
+	}
+	if err := Authorize(requestToken(r), scope); err != nil {
+		writeError(w, err)
+		return false
+	}
+	return true
+}
+
+func handleAPI(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r) {
+		return
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+
+	switch {
+	case len(path) == 1 && path[0] == "chains":
+		handleAPIChains(w, r)
+	case len(path) == 2 && path[0] == "chains":
+		handleAPIChain(w, r, path[1])
+	case len(path) == 3 && path[0] == "chains" && path[2] == "blocks":
+		handleAPIBlocks(w, r, path[1])
+	case len(path) == 4 && path[0] == "chains" && path[2] == "blocks":
+		handleAPIBlock(w, r, path[1], path[3])
+	case len(path) == 2 && path[0] == "blocks":
+		handleAPIBlockByHash(w, r, path[1])
+	case len(path) == 1 && path[0] == "peers":
+		handleAPIPeers(w, r)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]apiError{"error": {"NotFound", "unknown path"}})
+	}
+}
+
+// Blocks are published to the hub once however many times the service starts
+var hubHooked sync.Once
+
+// Block stream and HTTP API for applications
+func APIService(ctx context.Context) {
+	// WatchBlocks of the manage service streams blocks of the hub too
+	hubHooked.Do(func() {
+		blockchain.AddBlockSavedHook(sharedHub.publish)
+	})
+
+	if !utils.GetBool("api.enabled") {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/blocks", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			handleStream(w, r)
+		}
+	})
+	mux.HandleFunc(apiPrefix, handleAPI)
+
+	host := utils.GetString("api.host")
+	addr := fmt.Sprintf("%v:%v", host, utils.GetString("api.port"))
+	WarnIfExposed("api", host, false)
+	utils.L.Infof("api service start at %v", addr)
+	if err := serveHTTP(ctx, &http.Server{Addr: addr, Handler: mux}); err != nil {
+		utils.L.Fatal(err)
+	}
+}
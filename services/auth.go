@@ -0,0 +1,166 @@
+package services
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/Infnote/infnotechain/utils"
+	"io/ioutil"
+	"net"
+	"strings"
+)
+
+// Scope of a token, admin scope includes read scope
+type Scope int
+
+const (
+	ScopeRead Scope = iota
+	ScopeAdmin
+)
+
+type token struct {
+	Token string
+	Scope string
+}
+
+func (s Scope) String() string {
+	if s == ScopeAdmin {
+		return "admin"
+	}
+	return "read"
+}
+
+// Tokens in 'manage.tokens', authorization is disabled when no token configured
+func tokens() map[string]Scope {
+	var list []token
+	if err := utils.UnmarshalKey("manage.tokens", &list); err != nil {
+		utils.L.Warningf("invalid 'manage.tokens': %v", err)
+	}
+
+	result := map[string]Scope{}
+	for _, t := range list {
+		if len(t.Token) == 0 {
+			continue
+		}
+		if t.Scope == "admin" {
+			result[t.Token] = ScopeAdmin
+		} else {
+			result[t.Token] = ScopeRead
+		}
+	}
+	return result
+}
+
+func AuthEnabled() bool {
+	return len(tokens()) > 0
+}
+
+// Check a bearer token or API key against the required scope
+func Authorize(credential string, required Scope) error {
+	all := tokens()
+	if len(all) == 0 {
+		return nil
+	}
+
+	credential = strings.TrimSpace(strings.TrimPrefix(credential, "Bearer "))
+	if len(credential) == 0 {
+		return manageError(Unauthenticated, "token is required")
+	}
+
+	for t, scope := range all {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(credential)) != 1 {
+			continue
+		}
+		if scope < required {
+			return manageError(PermissionDenied, "token of %v scope is not allowed, %v scope required", scope, required)
+		}
+		return nil
+	}
+	return manageError(Unauthenticated, "invalid token")
+}
+
+// TLS of the manage service from 'manage.tls', nil when no certificate configured.
+// Client certificates are required and verified when 'manage.tls.ca' is set.
+func ServerTLSConfig() (*tls.Config, error) {
+	certFile := utils.GetString("manage.tls.cert")
+	keyFile := utils.GetString("manage.tls.key")
+	if len(certFile) == 0 || len(keyFile) == 0 {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if ca := utils.GetString("manage.tls.ca"); len(ca) > 0 {
+		pool, err := loadCertPool(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// TLS of the cli from 'manage.client', nil when TLS is not enabled
+func ClientTLSConfig() (*tls.Config, error) {
+	if !utils.GetBool("manage.client.tls") {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName: utils.GetString("manage.client.servername"),
+		MinVersion: tls.VersionTLS12,
+	}
+	if ca := utils.GetString("manage.client.ca"); len(ca) > 0 {
+		pool, err := loadCertPool(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	certFile := utils.GetString("manage.client.cert")
+	keyFile := utils.GetString("manage.client.key")
+	if len(certFile) > 0 && len(keyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in %v", file)
+	}
+	return pool, nil
+}
+
+// Warn when the service can be reached from other hosts without protection
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && ip.IsLoopback())
+}
+
+func WarnIfExposed(name string, host string, secure bool) {
+	if isLoopback(host) {
+		return
+	}
+	if !AuthEnabled() {
+		utils.L.Warningf("%v service listens on %v without tokens, anyone reachable can control this node", name, host)
+	} else if !secure {
+		utils.L.Warningf("%v service listens on %v without TLS, tokens are sent in plain text", name, host)
+	}
+}
@@ -1,18 +1,34 @@
 package services
 
 import (
-	"bytes"
+	"context"
 	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
 	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/protocol"
 	"github.com/Infnote/infnotechain/utils"
-	"github.com/spf13/viper"
-	"net/http"
-	"net/url"
+	"github.com/Infnote/infnotechain/webhook"
+	"time"
 )
 
 var SharedServer *network.Server
 
+// Messages not taken by the writing loop of a peer in time are dropped
+// with the peer, so a stuck peer does not hold messages to others
+const peerWriteTimeout = time.Second
+
+// Queue a message to the peer, false if it is closed or dropped
+func send(peer *network.Peer, data []byte) bool {
+	if peer.Write(data, peerWriteTimeout) {
+		return true
+	}
+	if !peer.IsClosed() {
+		utils.L.With(utils.Fields{"peer": peer.Addr}).Warningf("peer %v is too slow to receive, disconnected", peer.Addr)
+		peer.Close()
+	}
+	return false
+}
+
 func handleMessages(peer *network.Peer) {
 	defer func() {
 		// recover when any error occurred in message processing
@@ -26,71 +42,141 @@ func handleMessages(peer *network.Peer) {
 		if !ok {
 			return
 		}
-		for _, v := range protocol.HandleJSONData(peer, data) {
-			peer.Send <- v
+		// messages are drained until the connection is closed
+		if peer.Rejected {
+			continue
+		}
+		for _, v := range protocol.HandleData(peer, data) {
+			if !send(peer, v) {
+				break
+			}
+		}
+		if peer.Rejected {
+			utils.L.With(utils.Fields{"peer": peer.Addr}).Warningf("peer %v is not a %v node, disconnected", peer.Addr, utils.Network())
+			network.SharedStorage().DeletePeer(peer)
+			peer.Close()
 		}
 	}
 }
 
-func handleBroadcast() {
+func handleBroadcast(ctx context.Context) {
 	for {
-		broadcast := <-protocol.BroadcastChannel
-		utils.L.Debugf("broadcast a block")
-		for _, peer := range SharedServer.Peers {
-			if peer != broadcast.Sender {
-				peer.Send <- broadcast.Message().Serialize()
+		select {
+		case <-ctx.Done():
+			return
+		case announce := <-protocol.AnnounceChannel:
+			if ctx.Err() != nil {
+				return
+			}
+			utils.L.With(utils.Fields{"chain_id": announce.ChainID, "height": announce.Height}).Debugf("announce a block")
+			msg := protocol.NewMessage(announce)
+			for _, peer := range SharedServer.Peers() {
+				if peer != announce.Sender && protocol.IsTrusted(peer) &&
+					protocol.IsSubscribed(peer, announce.ChainID, announce.Height) {
+					send(peer, msg.SerializeFor(peer))
+				}
+			}
+		case behavior := <-protocol.GossipChannel:
+			if ctx.Err() != nil {
+				return
+			}
+			utils.L.Debugf("gossip to all peers:\n%v", behavior)
+			msg := protocol.NewMessage(behavior)
+			for _, peer := range SharedServer.Peers() {
+				send(peer, msg.SerializeFor(peer))
 			}
 		}
 	}
 }
 
-func handlePeers(server *network.Server) {
+// When the context is done, all peers are closed and
+// it returns after every peer left or timeout
+func handlePeers(ctx context.Context, server *network.Server) {
+	done := ctx.Done()
+	var timeout <-chan time.Time
 	for {
 		select {
+		case <-done:
+			utils.L.Infof("closing %v peers", server.PeerCount())
+			for _, peer := range server.Peers() {
+				peer.Close()
+			}
+			done = nil
+			timeout = time.After(ShutdownTimeout())
+		case <-timeout:
+			utils.L.Warningf("%v peers are not closed in time", server.PeerCount())
+			return
 		case peer := <-server.In:
-			utils.L.Infof("incoming peer: %v", peer.Addr)
-			server.Peers[peer.Addr] = peer
-			peer.Send <- protocol.NewMessage(protocol.NewInfo()).Serialize()
+			if ctx.Err() != nil {
+				peer.Close()
+				continue
+			}
+			utils.L.With(utils.Fields{"peer": peer.Addr}).Infof("incoming peer: %v", peer.Addr)
+			server.AddPeer(peer)
+			if send(peer, protocol.NewMessage(protocol.NewInfoFor(peer)).Serialize()) {
+				send(peer, protocol.NewMessage(protocol.NewSubscribe()).Serialize())
+			}
 			go handleMessages(peer)
 		case peer := <-server.Out:
-			utils.L.Infof("outcoming peer: %v", peer.Addr)
-			delete(server.Peers, peer.Addr)
+			utils.L.With(utils.Fields{"peer": peer.Addr}).Infof("outcoming peer: %v", peer.Addr)
+			server.RemovePeer(peer)
+			protocol.RemovePeer(peer)
+		}
+		updatePeerMetrics(server)
+		if ctx.Err() != nil && server.PeerCount() == 0 {
+			return
 		}
 	}
 }
 
-func addHook() {
-	hook := viper.GetString("hooks.block")
-	if len(hook) > 0 {
-		addr, err := url.Parse(hook)
-		if err != nil {
-			utils.L.Warning("%v: %v", addr)
-			return
-		}
-		blockchain.BlockSavedHook = func(block *blockchain.Block) {
-			_, err := http.Post(
-				addr.String(),
-				"application/json",
-				bytes.NewBuffer(block.Serialize()))
-			if err != nil {
-				utils.L.Warning("%v", err)
-			}
+// Peers connected by this node are outbound
+func updatePeerMetrics(server *network.Server) {
+	peers := map[string]int64{"inbound": 0, "outbound": 0}
+	for _, peer := range server.Peers() {
+		if peer.IsServer {
+			peers["outbound"]++
+		} else {
+			peers["inbound"]++
 		}
 	}
-
+	metrics.Peers.Set(peers)
 }
 
-func PeerService() {
+// Stopped when the context is done, cached blocks are committed
+// after all peers closed
+func PeerService(ctx context.Context) {
 	if SharedServer != nil {
 		return
 	}
 
+	blockchain.PruneChains()
+
 	SharedServer = network.NewServer()
-	go handlePeers(SharedServer)
-	go handleBroadcast()
+	closed := make(chan struct{})
+	go func() {
+		handlePeers(ctx, SharedServer)
+		close(closed)
+	}()
+	go handleBroadcast(ctx)
+	go protocol.SharedScheduler.Run(ctx)
 
-	addHook()
+	Start(ctx, webhook.Serve)
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), ShutdownTimeout())
+		defer cancel()
+		SharedServer.Shutdown(shutdown)
+	}()
 
 	utils.L.Info("network service start")
 	SharedServer.Serve()
+
+	<-closed
+	// blocks may be created until manage and API services stopped
+	if !WaitWriters(ShutdownTimeout()) {
+		utils.L.Warning("manage and API services are not stopped in time")
+	}
+	blockchain.FlushChains()
+	utils.L.Info("network service stopped")
 }
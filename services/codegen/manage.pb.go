@@ -335,6 +335,7 @@ type BlockResponse struct {
 	Hash                 string   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
 	Signature            string   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
 	Payload              []byte   `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	ChainID              string   `protobuf:"bytes,7,opt,name=chainID,proto3" json:"chainID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -407,12 +408,549 @@ func (m *BlockResponse) GetPayload() []byte {
 	return nil
 }
 
+func (m *BlockResponse) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	ChainID              string   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Replay               bool     `protobuf:"varint,2,opt,name=replay,proto3" json:"replay,omitempty"`
+	From                 uint64   `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{7}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetReplay() bool {
+	if m != nil {
+		return m.Replay
+	}
+	return false
+}
+
+func (m *WatchRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+type NodeStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeStatusRequest) Reset()         { *m = NodeStatusRequest{} }
+func (m *NodeStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeStatusRequest) ProtoMessage()    {}
+func (*NodeStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{8}
+}
+
+func (m *NodeStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatusRequest.Unmarshal(m, b)
+}
+func (m *NodeStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *NodeStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatusRequest.Merge(m, src)
+}
+func (m *NodeStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeStatusRequest.Size(m)
+}
+func (m *NodeStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStatusRequest proto.InternalMessageInfo
+
+type ChainStatus struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Count                uint64   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Best                 uint64   `protobuf:"varint,3,opt,name=best,proto3" json:"best,omitempty"`
+	Progress             float64  `protobuf:"fixed64,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChainStatus) Reset()         { *m = ChainStatus{} }
+func (m *ChainStatus) String() string { return proto.CompactTextString(m) }
+func (*ChainStatus) ProtoMessage()    {}
+func (*ChainStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{9}
+}
+
+func (m *ChainStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainStatus.Unmarshal(m, b)
+}
+func (m *ChainStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainStatus.Marshal(b, m, deterministic)
+}
+func (m *ChainStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainStatus.Merge(m, src)
+}
+func (m *ChainStatus) XXX_Size() int {
+	return xxx_messageInfo_ChainStatus.Size(m)
+}
+func (m *ChainStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainStatus proto.InternalMessageInfo
+
+func (m *ChainStatus) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChainStatus) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetBest() uint64 {
+	if m != nil {
+		return m.Best
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetProgress() float64 {
+	if m != nil {
+		return m.Progress
+	}
+	return 0
+}
+
+type NodeStatusResponse struct {
+	Version              string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Protocol             string            `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Uptime               int64             `protobuf:"varint,3,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	NodeID               string            `protobuf:"bytes,4,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Config               map[string]string `protobuf:"bytes,5,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Inbound              int32             `protobuf:"varint,6,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	Outbound             int32             `protobuf:"varint,7,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	Chains               []*ChainStatus    `protobuf:"bytes,8,rep,name=chains,proto3" json:"chains,omitempty"`
+	BytesReceived        uint64            `protobuf:"varint,9,opt,name=bytesReceived,proto3" json:"bytesReceived,omitempty"`
+	BytesSent            uint64            `protobuf:"varint,10,opt,name=bytesSent,proto3" json:"bytesSent,omitempty"`
+	MessagesReceived     map[string]uint64 `protobuf:"bytes,11,rep,name=messagesReceived,proto3" json:"messagesReceived,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	MessagesSent         map[string]uint64 `protobuf:"bytes,12,rep,name=messagesSent,proto3" json:"messagesSent,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	DatabaseSize         int64             `protobuf:"varint,13,opt,name=databaseSize,proto3" json:"databaseSize,omitempty"`
+	PayloadSize          int64             `protobuf:"varint,14,opt,name=payloadSize,proto3" json:"payloadSize,omitempty"`
+	Network              string            `protobuf:"bytes,15,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NodeStatusResponse) Reset()         { *m = NodeStatusResponse{} }
+func (m *NodeStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeStatusResponse) ProtoMessage()    {}
+func (*NodeStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{10}
+}
+
+func (m *NodeStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatusResponse.Unmarshal(m, b)
+}
+func (m *NodeStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *NodeStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatusResponse.Merge(m, src)
+}
+func (m *NodeStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeStatusResponse.Size(m)
+}
+func (m *NodeStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStatusResponse proto.InternalMessageInfo
+
+func (m *NodeStatusResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NodeStatusResponse) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *NodeStatusResponse) GetUptime() int64 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetNodeID() string {
+	if m != nil {
+		return m.NodeID
+	}
+	return ""
+}
+
+func (m *NodeStatusResponse) GetConfig() map[string]string {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *NodeStatusResponse) GetInbound() int32 {
+	if m != nil {
+		return m.Inbound
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetOutbound() int32 {
+	if m != nil {
+		return m.Outbound
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetChains() []*ChainStatus {
+	if m != nil {
+		return m.Chains
+	}
+	return nil
+}
+
+func (m *NodeStatusResponse) GetBytesReceived() uint64 {
+	if m != nil {
+		return m.BytesReceived
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetBytesSent() uint64 {
+	if m != nil {
+		return m.BytesSent
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetMessagesReceived() map[string]uint64 {
+	if m != nil {
+		return m.MessagesReceived
+	}
+	return nil
+}
+
+func (m *NodeStatusResponse) GetMessagesSent() map[string]uint64 {
+	if m != nil {
+		return m.MessagesSent
+	}
+	return nil
+}
+
+func (m *NodeStatusResponse) GetDatabaseSize() int64 {
+	if m != nil {
+		return m.DatabaseSize
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetPayloadSize() int64 {
+	if m != nil {
+		return m.PayloadSize
+	}
+	return 0
+}
+
+func (m *NodeStatusResponse) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type ReloadConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReloadConfigRequest) Reset()         { *m = ReloadConfigRequest{} }
+func (m *ReloadConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ReloadConfigRequest) ProtoMessage()    {}
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{11}
+}
+
+func (m *ReloadConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReloadConfigRequest.Unmarshal(m, b)
+}
+func (m *ReloadConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReloadConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *ReloadConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadConfigRequest.Merge(m, src)
+}
+func (m *ReloadConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ReloadConfigRequest.Size(m)
+}
+func (m *ReloadConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReloadConfigRequest proto.InternalMessageInfo
+
+type ReloadConfigResponse struct {
+	Applied              []string `protobuf:"bytes,1,rep,name=applied,proto3" json:"applied,omitempty"`
+	Restart              []string `protobuf:"bytes,2,rep,name=restart,proto3" json:"restart,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReloadConfigResponse) Reset()         { *m = ReloadConfigResponse{} }
+func (m *ReloadConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*ReloadConfigResponse) ProtoMessage()    {}
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{12}
+}
+
+func (m *ReloadConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReloadConfigResponse.Unmarshal(m, b)
+}
+func (m *ReloadConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReloadConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *ReloadConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadConfigResponse.Merge(m, src)
+}
+func (m *ReloadConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_ReloadConfigResponse.Size(m)
+}
+func (m *ReloadConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReloadConfigResponse proto.InternalMessageInfo
+
+func (m *ReloadConfigResponse) GetApplied() []string {
+	if m != nil {
+		return m.Applied
+	}
+	return nil
+}
+
+func (m *ReloadConfigResponse) GetRestart() []string {
+	if m != nil {
+		return m.Restart
+	}
+	return nil
+}
+
+type BlockHashRequest struct {
+	ChainID              string   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Hash                 string   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Remote               bool     `protobuf:"varint,3,opt,name=remote,proto3" json:"remote,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHashRequest) Reset()         { *m = BlockHashRequest{} }
+func (m *BlockHashRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockHashRequest) ProtoMessage()    {}
+func (*BlockHashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{13}
+}
+
+func (m *BlockHashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHashRequest.Unmarshal(m, b)
+}
+func (m *BlockHashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHashRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockHashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHashRequest.Merge(m, src)
+}
+func (m *BlockHashRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockHashRequest.Size(m)
+}
+func (m *BlockHashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHashRequest proto.InternalMessageInfo
+
+func (m *BlockHashRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *BlockHashRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *BlockHashRequest) GetRemote() bool {
+	if m != nil {
+		return m.Remote
+	}
+	return false
+}
+
+type AvailableChainResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Author               string   `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Website              string   `protobuf:"bytes,4,opt,name=website,proto3" json:"website,omitempty"`
+	Email                string   `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Desc                 string   `protobuf:"bytes,6,opt,name=desc,proto3" json:"desc,omitempty"`
+	Count                uint64   `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	Last                 int64    `protobuf:"varint,8,opt,name=last,proto3" json:"last,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AvailableChainResponse) Reset()         { *m = AvailableChainResponse{} }
+func (m *AvailableChainResponse) String() string { return proto.CompactTextString(m) }
+func (*AvailableChainResponse) ProtoMessage()    {}
+func (*AvailableChainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{14}
+}
+
+func (m *AvailableChainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AvailableChainResponse.Unmarshal(m, b)
+}
+func (m *AvailableChainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AvailableChainResponse.Marshal(b, m, deterministic)
+}
+func (m *AvailableChainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AvailableChainResponse.Merge(m, src)
+}
+func (m *AvailableChainResponse) XXX_Size() int {
+	return xxx_messageInfo_AvailableChainResponse.Size(m)
+}
+func (m *AvailableChainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AvailableChainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AvailableChainResponse proto.InternalMessageInfo
+
+func (m *AvailableChainResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetWebsite() string {
+	if m != nil {
+		return m.Website
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetDesc() string {
+	if m != nil {
+		return m.Desc
+	}
+	return ""
+}
+
+func (m *AvailableChainResponse) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *AvailableChainResponse) GetLast() int64 {
+	if m != nil {
+		return m.Last
+	}
+	return 0
+}
+
 type ChainCreationRequest struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Author               string   `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
 	Website              string   `protobuf:"bytes,3,opt,name=website,proto3" json:"website,omitempty"`
 	Email                string   `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
 	Desc                 string   `protobuf:"bytes,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	Mnemonic             bool     `protobuf:"varint,6,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	Passphrase           string   `protobuf:"bytes,7,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -422,7 +960,7 @@ func (m *ChainCreationRequest) Reset()         { *m = ChainCreationRequest{} }
 func (m *ChainCreationRequest) String() string { return proto.CompactTextString(m) }
 func (*ChainCreationRequest) ProtoMessage()    {}
 func (*ChainCreationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_519fa8ed5ffbbc8f, []int{7}
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{15}
 }
 
 func (m *ChainCreationRequest) XXX_Unmarshal(b []byte) error {
@@ -478,10 +1016,25 @@ func (m *ChainCreationRequest) GetDesc() string {
 	return ""
 }
 
+func (m *ChainCreationRequest) GetMnemonic() bool {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return false
+}
+
+func (m *ChainCreationRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
 type ChainCreationResponse struct {
 	Ref                  int64    `protobuf:"varint,1,opt,name=ref,proto3" json:"ref,omitempty"`
 	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	Wif                  string   `protobuf:"bytes,3,opt,name=wif,proto3" json:"wif,omitempty"`
+	Mnemonic             string   `protobuf:"bytes,4,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -491,7 +1044,7 @@ func (m *ChainCreationResponse) Reset()         { *m = ChainCreationResponse{} }
 func (m *ChainCreationResponse) String() string { return proto.CompactTextString(m) }
 func (*ChainCreationResponse) ProtoMessage()    {}
 func (*ChainCreationResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_519fa8ed5ffbbc8f, []int{8}
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{16}
 }
 
 func (m *ChainCreationResponse) XXX_Unmarshal(b []byte) error {
@@ -530,127 +1083,440 @@ func (m *ChainCreationResponse) GetWif() string {
 	if m != nil {
 		return m.Wif
 	}
-	return ""
+	return ""
+}
+
+func (m *ChainCreationResponse) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+type ChainRecoveryRequest struct {
+	Mnemonic             string   `protobuf:"bytes,1,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	Passphrase           string   `protobuf:"bytes,2,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	ChainID              string   `protobuf:"bytes,3,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChainRecoveryRequest) Reset()         { *m = ChainRecoveryRequest{} }
+func (m *ChainRecoveryRequest) String() string { return proto.CompactTextString(m) }
+func (*ChainRecoveryRequest) ProtoMessage()    {}
+func (*ChainRecoveryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{17}
+}
+
+func (m *ChainRecoveryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainRecoveryRequest.Unmarshal(m, b)
+}
+func (m *ChainRecoveryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainRecoveryRequest.Marshal(b, m, deterministic)
+}
+func (m *ChainRecoveryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainRecoveryRequest.Merge(m, src)
+}
+func (m *ChainRecoveryRequest) XXX_Size() int {
+	return xxx_messageInfo_ChainRecoveryRequest.Size(m)
+}
+func (m *ChainRecoveryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainRecoveryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainRecoveryRequest proto.InternalMessageInfo
+
+func (m *ChainRecoveryRequest) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+func (m *ChainRecoveryRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+func (m *ChainRecoveryRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+type BlockCreationRequest struct {
+	ChainID              string   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockCreationRequest) Reset()         { *m = BlockCreationRequest{} }
+func (m *BlockCreationRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockCreationRequest) ProtoMessage()    {}
+func (*BlockCreationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{18}
+}
+
+func (m *BlockCreationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockCreationRequest.Unmarshal(m, b)
+}
+func (m *BlockCreationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockCreationRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockCreationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockCreationRequest.Merge(m, src)
+}
+func (m *BlockCreationRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockCreationRequest.Size(m)
+}
+func (m *BlockCreationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockCreationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockCreationRequest proto.InternalMessageInfo
+
+func (m *BlockCreationRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *BlockCreationRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type BlockCreationResponse struct {
+	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Time                 uint64   `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	PrevHash             string   `protobuf:"bytes,3,opt,name=prevHash,proto3" json:"prevHash,omitempty"`
+	Hash                 string   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Signature            string   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockCreationResponse) Reset()         { *m = BlockCreationResponse{} }
+func (m *BlockCreationResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockCreationResponse) ProtoMessage()    {}
+func (*BlockCreationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{19}
+}
+
+func (m *BlockCreationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockCreationResponse.Unmarshal(m, b)
+}
+func (m *BlockCreationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockCreationResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockCreationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockCreationResponse.Merge(m, src)
+}
+func (m *BlockCreationResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockCreationResponse.Size(m)
+}
+func (m *BlockCreationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockCreationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockCreationResponse proto.InternalMessageInfo
+
+func (m *BlockCreationResponse) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockCreationResponse) GetTime() uint64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *BlockCreationResponse) GetPrevHash() string {
+	if m != nil {
+		return m.PrevHash
+	}
+	return ""
+}
+
+func (m *BlockCreationResponse) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *BlockCreationResponse) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+type GenerateBlocksRequest struct {
+	ChainID              string   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Count                int32    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GenerateBlocksRequest) Reset()         { *m = GenerateBlocksRequest{} }
+func (m *GenerateBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*GenerateBlocksRequest) ProtoMessage()    {}
+func (*GenerateBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{20}
+}
+
+func (m *GenerateBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GenerateBlocksRequest.Unmarshal(m, b)
+}
+func (m *GenerateBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GenerateBlocksRequest.Marshal(b, m, deterministic)
+}
+func (m *GenerateBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateBlocksRequest.Merge(m, src)
+}
+func (m *GenerateBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_GenerateBlocksRequest.Size(m)
+}
+func (m *GenerateBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GenerateBlocksRequest proto.InternalMessageInfo
+
+func (m *GenerateBlocksRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *GenerateBlocksRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type GenerateBlocksResponse struct {
+	Hashes               []string `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GenerateBlocksResponse) Reset()         { *m = GenerateBlocksResponse{} }
+func (m *GenerateBlocksResponse) String() string { return proto.CompactTextString(m) }
+func (*GenerateBlocksResponse) ProtoMessage()    {}
+func (*GenerateBlocksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{21}
+}
+
+func (m *GenerateBlocksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GenerateBlocksResponse.Unmarshal(m, b)
+}
+func (m *GenerateBlocksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GenerateBlocksResponse.Marshal(b, m, deterministic)
+}
+func (m *GenerateBlocksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateBlocksResponse.Merge(m, src)
+}
+func (m *GenerateBlocksResponse) XXX_Size() int {
+	return xxx_messageInfo_GenerateBlocksResponse.Size(m)
+}
+func (m *GenerateBlocksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateBlocksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GenerateBlocksResponse proto.InternalMessageInfo
+
+func (m *GenerateBlocksResponse) GetHashes() []string {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type WebhookListRequest struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WebhookListRequest) Reset()         { *m = WebhookListRequest{} }
+func (m *WebhookListRequest) String() string { return proto.CompactTextString(m) }
+func (*WebhookListRequest) ProtoMessage()    {}
+func (*WebhookListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{22}
+}
+
+func (m *WebhookListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookListRequest.Unmarshal(m, b)
+}
+func (m *WebhookListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookListRequest.Marshal(b, m, deterministic)
+}
+func (m *WebhookListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookListRequest.Merge(m, src)
+}
+func (m *WebhookListRequest) XXX_Size() int {
+	return xxx_messageInfo_WebhookListRequest.Size(m)
+}
+func (m *WebhookListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookListRequest proto.InternalMessageInfo
+
+func (m *WebhookListRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
 }
 
-type BlockCreationRequest struct {
-	ChainID              string   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
-	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+type WebhookRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *BlockCreationRequest) Reset()         { *m = BlockCreationRequest{} }
-func (m *BlockCreationRequest) String() string { return proto.CompactTextString(m) }
-func (*BlockCreationRequest) ProtoMessage()    {}
-func (*BlockCreationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_519fa8ed5ffbbc8f, []int{9}
+func (m *WebhookRequest) Reset()         { *m = WebhookRequest{} }
+func (m *WebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*WebhookRequest) ProtoMessage()    {}
+func (*WebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{23}
 }
 
-func (m *BlockCreationRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BlockCreationRequest.Unmarshal(m, b)
+func (m *WebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookRequest.Unmarshal(m, b)
 }
-func (m *BlockCreationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BlockCreationRequest.Marshal(b, m, deterministic)
+func (m *WebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookRequest.Marshal(b, m, deterministic)
 }
-func (m *BlockCreationRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlockCreationRequest.Merge(m, src)
+func (m *WebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookRequest.Merge(m, src)
 }
-func (m *BlockCreationRequest) XXX_Size() int {
-	return xxx_messageInfo_BlockCreationRequest.Size(m)
+func (m *WebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_WebhookRequest.Size(m)
 }
-func (m *BlockCreationRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_BlockCreationRequest.DiscardUnknown(m)
+func (m *WebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BlockCreationRequest proto.InternalMessageInfo
-
-func (m *BlockCreationRequest) GetChainID() string {
-	if m != nil {
-		return m.ChainID
-	}
-	return ""
-}
+var xxx_messageInfo_WebhookRequest proto.InternalMessageInfo
 
-func (m *BlockCreationRequest) GetPayload() []byte {
+func (m *WebhookRequest) GetId() int64 {
 	if m != nil {
-		return m.Payload
+		return m.Id
 	}
-	return nil
+	return 0
 }
 
-type BlockCreationResponse struct {
-	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
-	Time                 uint64   `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
-	PrevHash             string   `protobuf:"bytes,3,opt,name=prevHash,proto3" json:"prevHash,omitempty"`
-	Hash                 string   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
-	Signature            string   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+type WebhookDeliveryResponse struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	ChainID              string   `protobuf:"bytes,3,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Height               uint64   `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Attempts             int32    `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error                string   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Next                 int64    `protobuf:"varint,7,opt,name=next,proto3" json:"next,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *BlockCreationResponse) Reset()         { *m = BlockCreationResponse{} }
-func (m *BlockCreationResponse) String() string { return proto.CompactTextString(m) }
-func (*BlockCreationResponse) ProtoMessage()    {}
-func (*BlockCreationResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_519fa8ed5ffbbc8f, []int{10}
+func (m *WebhookDeliveryResponse) Reset()         { *m = WebhookDeliveryResponse{} }
+func (m *WebhookDeliveryResponse) String() string { return proto.CompactTextString(m) }
+func (*WebhookDeliveryResponse) ProtoMessage()    {}
+func (*WebhookDeliveryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{24}
 }
 
-func (m *BlockCreationResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BlockCreationResponse.Unmarshal(m, b)
+func (m *WebhookDeliveryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookDeliveryResponse.Unmarshal(m, b)
 }
-func (m *BlockCreationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BlockCreationResponse.Marshal(b, m, deterministic)
+func (m *WebhookDeliveryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookDeliveryResponse.Marshal(b, m, deterministic)
 }
-func (m *BlockCreationResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlockCreationResponse.Merge(m, src)
+func (m *WebhookDeliveryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookDeliveryResponse.Merge(m, src)
 }
-func (m *BlockCreationResponse) XXX_Size() int {
-	return xxx_messageInfo_BlockCreationResponse.Size(m)
+func (m *WebhookDeliveryResponse) XXX_Size() int {
+	return xxx_messageInfo_WebhookDeliveryResponse.Size(m)
 }
-func (m *BlockCreationResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_BlockCreationResponse.DiscardUnknown(m)
+func (m *WebhookDeliveryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookDeliveryResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BlockCreationResponse proto.InternalMessageInfo
+var xxx_messageInfo_WebhookDeliveryResponse proto.InternalMessageInfo
 
-func (m *BlockCreationResponse) GetHeight() uint64 {
+func (m *WebhookDeliveryResponse) GetId() int64 {
 	if m != nil {
-		return m.Height
+		return m.Id
 	}
 	return 0
 }
 
-func (m *BlockCreationResponse) GetTime() uint64 {
+func (m *WebhookDeliveryResponse) GetUrl() string {
 	if m != nil {
-		return m.Time
+		return m.Url
 	}
-	return 0
+	return ""
 }
 
-func (m *BlockCreationResponse) GetPrevHash() string {
+func (m *WebhookDeliveryResponse) GetChainID() string {
 	if m != nil {
-		return m.PrevHash
+		return m.ChainID
 	}
 	return ""
 }
 
-func (m *BlockCreationResponse) GetHash() string {
+func (m *WebhookDeliveryResponse) GetHeight() uint64 {
 	if m != nil {
-		return m.Hash
+		return m.Height
 	}
-	return ""
+	return 0
 }
 
-func (m *BlockCreationResponse) GetSignature() string {
+func (m *WebhookDeliveryResponse) GetAttempts() int32 {
 	if m != nil {
-		return m.Signature
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *WebhookDeliveryResponse) GetError() string {
+	if m != nil {
+		return m.Error
 	}
 	return ""
 }
 
+func (m *WebhookDeliveryResponse) GetNext() int64 {
+	if m != nil {
+		return m.Next
+	}
+	return 0
+}
+
 type CommonResponse struct {
 	Success              bool     `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -663,7 +1529,7 @@ func (m *CommonResponse) Reset()         { *m = CommonResponse{} }
 func (m *CommonResponse) String() string { return proto.CompactTextString(m) }
 func (*CommonResponse) ProtoMessage()    {}
 func (*CommonResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_519fa8ed5ffbbc8f, []int{11}
+	return fileDescriptor_519fa8ed5ffbbc8f, []int{25}
 }
 
 func (m *CommonResponse) XXX_Unmarshal(b []byte) error {
@@ -706,58 +1572,127 @@ func init() {
 	proto.RegisterType((*ChainResponse)(nil), "manage.ChainResponse")
 	proto.RegisterType((*BlockRequest)(nil), "manage.BlockRequest")
 	proto.RegisterType((*BlockResponse)(nil), "manage.BlockResponse")
+	proto.RegisterType((*WatchRequest)(nil), "manage.WatchRequest")
+	proto.RegisterType((*NodeStatusRequest)(nil), "manage.NodeStatusRequest")
+	proto.RegisterType((*ChainStatus)(nil), "manage.ChainStatus")
+	proto.RegisterType((*NodeStatusResponse)(nil), "manage.NodeStatusResponse")
+	proto.RegisterMapType((map[string]string)(nil), "manage.NodeStatusResponse.ConfigEntry")
+	proto.RegisterMapType((map[string]uint64)(nil), "manage.NodeStatusResponse.MessagesReceivedEntry")
+	proto.RegisterMapType((map[string]uint64)(nil), "manage.NodeStatusResponse.MessagesSentEntry")
+	proto.RegisterType((*ReloadConfigRequest)(nil), "manage.ReloadConfigRequest")
+	proto.RegisterType((*ReloadConfigResponse)(nil), "manage.ReloadConfigResponse")
+	proto.RegisterType((*BlockHashRequest)(nil), "manage.BlockHashRequest")
+	proto.RegisterType((*AvailableChainResponse)(nil), "manage.AvailableChainResponse")
 	proto.RegisterType((*ChainCreationRequest)(nil), "manage.ChainCreationRequest")
 	proto.RegisterType((*ChainCreationResponse)(nil), "manage.ChainCreationResponse")
+	proto.RegisterType((*ChainRecoveryRequest)(nil), "manage.ChainRecoveryRequest")
 	proto.RegisterType((*BlockCreationRequest)(nil), "manage.BlockCreationRequest")
 	proto.RegisterType((*BlockCreationResponse)(nil), "manage.BlockCreationResponse")
+	proto.RegisterType((*GenerateBlocksRequest)(nil), "manage.GenerateBlocksRequest")
+	proto.RegisterType((*GenerateBlocksResponse)(nil), "manage.GenerateBlocksResponse")
+	proto.RegisterType((*WebhookListRequest)(nil), "manage.WebhookListRequest")
+	proto.RegisterType((*WebhookRequest)(nil), "manage.WebhookRequest")
+	proto.RegisterType((*WebhookDeliveryResponse)(nil), "manage.WebhookDeliveryResponse")
 	proto.RegisterType((*CommonResponse)(nil), "manage.CommonResponse")
 }
 
 func init() { proto.RegisterFile("manage.proto", fileDescriptor_519fa8ed5ffbbc8f) }
 
 var fileDescriptor_519fa8ed5ffbbc8f = []byte{
-	// 652 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0xdd, 0x6e, 0x13, 0x3d,
-	0x10, 0xd5, 0x26, 0x9b, 0x34, 0x99, 0xa4, 0xfd, 0x2a, 0x7f, 0x69, 0x59, 0x45, 0x05, 0x85, 0xbd,
-	0xca, 0x55, 0x55, 0x81, 0x84, 0xa0, 0xbd, 0xa1, 0xa4, 0xa2, 0xb4, 0x14, 0x09, 0xed, 0x1b, 0xb8,
-	0xbb, 0xd3, 0xc6, 0x6a, 0xd6, 0x0e, 0xb6, 0xd3, 0xaa, 0x3c, 0x00, 0xf7, 0x3c, 0x03, 0x6f, 0xc1,
-	0xd3, 0x21, 0xff, 0x25, 0x9b, 0x28, 0x45, 0x94, 0x1b, 0xee, 0xe6, 0xcc, 0xce, 0x39, 0x33, 0x3e,
-	0x13, 0x3b, 0xd0, 0x2d, 0x29, 0xa7, 0xd7, 0xb8, 0x3f, 0x95, 0x42, 0x0b, 0xd2, 0x74, 0x28, 0x3d,
-	0x82, 0xff, 0x3e, 0x23, 0xca, 0x0b, 0xa6, 0x74, 0x86, 0x5f, 0x66, 0xa8, 0x34, 0xe9, 0x41, 0x23,
-	0x17, 0x33, 0xae, 0x93, 0x68, 0x10, 0x0d, 0x1b, 0x99, 0x03, 0x84, 0x40, 0xac, 0xef, 0xa7, 0x98,
-	0xd4, 0x6c, 0xd2, 0xc6, 0xe9, 0x73, 0xe8, 0x18, 0x72, 0x20, 0x12, 0x88, 0x69, 0x51, 0x48, 0xcb,
-	0x6b, 0x67, 0x36, 0x4e, 0xbf, 0x42, 0xd7, 0x95, 0xa8, 0xa9, 0xe0, 0x0a, 0xd7, 0xd5, 0x98, 0x9c,
-	0xa4, 0xfc, 0x26, 0x48, 0x9b, 0xd8, 0xe4, 0x26, 0x54, 0xe9, 0xa4, 0x3e, 0x88, 0x86, 0xf5, 0xcc,
-	0xc6, 0x64, 0x17, 0x9a, 0x0a, 0xe5, 0x2d, 0xca, 0x24, 0x1e, 0x44, 0xc3, 0x56, 0xe6, 0x91, 0xc9,
-	0x0b, 0x3e, 0x61, 0x1c, 0x93, 0x86, 0xcb, 0x3b, 0x94, 0x3e, 0x83, 0xee, 0x68, 0x4c, 0x19, 0x0f,
-	0xf3, 0x6d, 0x41, 0x8d, 0x15, 0xbe, 0x73, 0x8d, 0x15, 0xe9, 0x29, 0x6c, 0xfa, 0xef, 0x7e, 0xb8,
-	0x6d, 0xa8, 0x4b, 0xbc, 0xb2, 0x15, 0xf5, 0xcc, 0x84, 0x9e, 0x52, 0x0b, 0x94, 0x85, 0x37, 0x66,
-	0xae, 0xd8, 0x7b, 0x93, 0x5e, 0x40, 0xf7, 0xdd, 0x44, 0xe4, 0x37, 0xa1, 0x51, 0x02, 0x1b, 0xb9,
-	0x11, 0x3e, 0x3b, 0xf1, 0xdd, 0x02, 0x34, 0xc7, 0xba, 0x92, 0xa2, 0xb4, 0x8a, 0x71, 0x66, 0x63,
-	0xd3, 0x43, 0x0b, 0x2f, 0x58, 0xd3, 0x22, 0xfd, 0x11, 0xc1, 0xa6, 0x97, 0xf3, 0x73, 0xed, 0x42,
-	0x73, 0x8c, 0xec, 0x7a, 0xec, 0x56, 0x12, 0x67, 0x1e, 0xd9, 0x9d, 0xb0, 0x12, 0x83, 0x9a, 0x89,
-	0x49, 0x1f, 0x5a, 0x53, 0x89, 0xb7, 0x1f, 0xa8, 0x1a, 0x5b, 0xcd, 0x76, 0x36, 0xc7, 0xa6, 0x7e,
-	0x6c, 0xf2, 0xb1, 0x33, 0xdf, 0xc4, 0x64, 0x0f, 0xda, 0x8a, 0x5d, 0x73, 0xaa, 0x67, 0xd2, 0xf9,
-	0xd7, 0xce, 0x16, 0x09, 0x73, 0x92, 0x29, 0xbd, 0x9f, 0x08, 0x5a, 0x24, 0xcd, 0x41, 0x34, 0xec,
-	0x66, 0x01, 0xa6, 0xdf, 0x22, 0xe8, 0x59, 0xf7, 0x46, 0x12, 0xa9, 0x66, 0x82, 0x57, 0x7e, 0x05,
-	0x9c, 0x96, 0x18, 0x36, 0x6c, 0x62, 0x73, 0x00, 0x3a, 0xd3, 0x63, 0x21, 0xbd, 0x95, 0x1e, 0x19,
-	0xf9, 0x3b, 0xbc, 0x54, 0x4c, 0xa3, 0x9f, 0x35, 0x40, 0x63, 0x34, 0x96, 0x94, 0x4d, 0xfc, 0xac,
-	0x0e, 0x18, 0xed, 0x02, 0x55, 0xee, 0xe7, 0xb4, 0x71, 0xfa, 0x11, 0x76, 0x56, 0xe6, 0xf8, 0xe3,
-	0x6d, 0x6e, 0x43, 0xfd, 0x8e, 0x5d, 0xf9, 0xd6, 0x26, 0x4c, 0xcf, 0xa1, 0x67, 0xad, 0x5f, 0x3d,
-	0xd4, 0xc3, 0x1b, 0xad, 0x38, 0x54, 0x5b, 0x76, 0xe8, 0x7b, 0x04, 0x3b, 0x2b, 0x62, 0xff, 0x7a,
-	0x9f, 0xe9, 0x5b, 0xd8, 0x1a, 0x89, 0xb2, 0xac, 0xcc, 0x92, 0xc0, 0x86, 0x9a, 0xe5, 0x39, 0x2a,
-	0x65, 0x87, 0x69, 0x65, 0x01, 0xda, 0x15, 0x48, 0x39, 0xdf, 0x99, 0x03, 0x2f, 0x7e, 0x36, 0xa0,
-	0x7d, 0xf6, 0x7e, 0xf4, 0xc9, 0x3e, 0x1f, 0xe4, 0x10, 0xda, 0xa7, 0xa8, 0xad, 0xff, 0x8a, 0xf4,
-	0xf6, 0xfd, 0x13, 0x53, 0xbd, 0x75, 0xfd, 0x9d, 0x95, 0xac, 0xeb, 0x7b, 0x10, 0x79, 0xae, 0x75,
-	0xa8, 0xc2, 0xad, 0x5e, 0xa4, 0x05, 0x77, 0xe9, 0x3e, 0x1c, 0x44, 0xe4, 0x1c, 0x3a, 0xd6, 0x55,
-	0xb4, 0xa2, 0x64, 0x6f, 0xa9, 0xc7, 0xca, 0xf2, 0xfa, 0x4f, 0x1f, 0xf8, 0xea, 0x1d, 0x98, 0x6b,
-	0xd9, 0x26, 0x0b, 0xad, 0x75, 0x3f, 0x84, 0x85, 0xd6, 0xfa, 0xcd, 0xbe, 0x86, 0xd6, 0x71, 0x51,
-	0xb8, 0xa1, 0xd6, 0xdb, 0xb1, 0x3b, 0xcf, 0x2e, 0xef, 0xe1, 0x08, 0x3a, 0x27, 0x38, 0xc1, 0x70,
-	0xa2, 0xc7, 0x92, 0x5b, 0xa7, 0xa8, 0xcd, 0x43, 0xab, 0xc8, 0x93, 0x50, 0xb3, 0xf2, 0xae, 0xf7,
-	0x7b, 0xd5, 0x0f, 0x15, 0x2f, 0x5f, 0xc1, 0xc6, 0x71, 0x51, 0x98, 0x24, 0xf9, 0x7f, 0xb9, 0xe4,
-	0xf7, 0x4d, 0x0f, 0xa1, 0x33, 0x12, 0x9c, 0x63, 0xae, 0xff, 0x8a, 0x7b, 0xc2, 0x54, 0x2e, 0x38,
-	0x7f, 0x3c, 0xf7, 0x0d, 0x80, 0x73, 0xea, 0xd1, 0xd4, 0xcb, 0xa6, 0xfd, 0xf3, 0x7b, 0xf9, 0x2b,
-	0x00, 0x00, 0xff, 0xff, 0x30, 0x2b, 0x60, 0xd0, 0x0c, 0x07, 0x00, 0x00,
+	// 1475 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x06, 0x25, 0x59, 0x96, 0x46, 0xb2, 0xe3, 0xac, 0x0f, 0xe1, 0xcf, 0x3f, 0xc9, 0xaf, 0x9f,
+	0x28, 0x0a, 0xa3, 0x2d, 0x02, 0x23, 0x05, 0x8a, 0x1c, 0x8a, 0xa4, 0x8e, 0xdc, 0xa8, 0x4e, 0x93,
+	0x36, 0xa0, 0x0b, 0xa4, 0x17, 0xbd, 0x59, 0x91, 0x63, 0x89, 0x30, 0xc5, 0x55, 0x77, 0x57, 0x4a,
+	0x95, 0xb7, 0xe8, 0xab, 0xb4, 0xd7, 0xbd, 0xe9, 0x9b, 0xf4, 0x4d, 0x8a, 0x3d, 0x90, 0x22, 0x69,
+	0x29, 0x4e, 0xd2, 0x8b, 0xde, 0xed, 0xcc, 0xce, 0x7c, 0x9c, 0xc3, 0xce, 0x41, 0x82, 0xee, 0x84,
+	0xa6, 0x74, 0x84, 0x77, 0xa6, 0x9c, 0x49, 0x46, 0x9a, 0x86, 0xf2, 0x1f, 0xc2, 0xb5, 0x97, 0x88,
+	0xfc, 0x79, 0x2c, 0x64, 0x80, 0x3f, 0xcf, 0x50, 0x48, 0xb2, 0x07, 0x1b, 0x21, 0x9b, 0xa5, 0xd2,
+	0x75, 0x7a, 0xce, 0xe1, 0x46, 0x60, 0x08, 0x42, 0xa0, 0x21, 0x17, 0x53, 0x74, 0x6b, 0x9a, 0xa9,
+	0xcf, 0xfe, 0xff, 0xa1, 0xa3, 0x94, 0x33, 0x45, 0x02, 0x0d, 0x1a, 0x45, 0x5c, 0xeb, 0xb5, 0x03,
+	0x7d, 0xf6, 0xdf, 0x40, 0xd7, 0x88, 0x88, 0x29, 0x4b, 0x05, 0xae, 0x92, 0x51, 0x3c, 0x4e, 0xd3,
+	0x8b, 0x0c, 0x5a, 0x9d, 0x15, 0x2f, 0xa1, 0x42, 0xba, 0xf5, 0x9e, 0x73, 0x58, 0x0f, 0xf4, 0x99,
+	0x1c, 0x40, 0x53, 0x20, 0x9f, 0x23, 0x77, 0x1b, 0x3d, 0xe7, 0xb0, 0x15, 0x58, 0x4a, 0xf1, 0x59,
+	0x9a, 0xc4, 0x29, 0xba, 0x1b, 0x86, 0x6f, 0x28, 0xff, 0x36, 0x74, 0xfb, 0x63, 0x1a, 0xa7, 0x99,
+	0x7d, 0xdb, 0x50, 0x8b, 0x23, 0xfb, 0xe5, 0x5a, 0x1c, 0xf9, 0x03, 0xd8, 0xb2, 0xf7, 0xd6, 0xb8,
+	0x1d, 0xa8, 0x73, 0x3c, 0xd7, 0x12, 0xf5, 0x40, 0x1d, 0xad, 0x4a, 0x2d, 0x53, 0x59, 0xc6, 0x46,
+	0xd9, 0xd5, 0xb0, 0xb1, 0xf1, 0x9f, 0x43, 0xf7, 0x49, 0xc2, 0xc2, 0x8b, 0xec, 0x43, 0x2e, 0x6c,
+	0x86, 0x0a, 0xf8, 0xf4, 0xc4, 0x7e, 0x2d, 0x23, 0x95, 0x5b, 0xe7, 0x9c, 0x4d, 0x34, 0x62, 0x23,
+	0xd0, 0x67, 0xf5, 0x0d, 0xc9, 0x2c, 0x60, 0x4d, 0x32, 0xff, 0x0f, 0x07, 0xb6, 0x2c, 0x9c, 0xb5,
+	0xeb, 0x00, 0x9a, 0x63, 0x8c, 0x47, 0x63, 0x93, 0x92, 0x46, 0x60, 0x29, 0x9d, 0x93, 0x78, 0x82,
+	0x19, 0x9a, 0x3a, 0x13, 0x0f, 0x5a, 0x53, 0x8e, 0xf3, 0x6f, 0xa8, 0x18, 0x6b, 0xcc, 0x76, 0x90,
+	0xd3, 0x4a, 0x7e, 0xac, 0xf8, 0x0d, 0x13, 0x7c, 0x75, 0x26, 0x37, 0xa1, 0x2d, 0xe2, 0x51, 0x4a,
+	0xe5, 0x8c, 0x9b, 0xf8, 0xb5, 0x83, 0x25, 0x43, 0x79, 0x32, 0xa5, 0x8b, 0x84, 0xd1, 0xc8, 0x6d,
+	0xf6, 0x9c, 0xc3, 0x6e, 0x90, 0x91, 0x45, 0x1f, 0x37, 0x4b, 0x3e, 0xfa, 0x3f, 0x40, 0xf7, 0x15,
+	0x95, 0xe1, 0xf8, 0xea, 0x68, 0x1c, 0x40, 0x93, 0xe3, 0x34, 0xa1, 0x0b, 0xed, 0x41, 0x2b, 0xb0,
+	0x54, 0x1e, 0xa5, 0xfa, 0x32, 0x4a, 0xfe, 0x2e, 0x5c, 0xff, 0x8e, 0x45, 0x78, 0x26, 0xa9, 0x9c,
+	0x09, 0x0b, 0xed, 0x87, 0xd0, 0xd1, 0x19, 0x34, 0xdc, 0x6a, 0x82, 0x97, 0xd9, 0xaa, 0x15, 0xb2,
+	0xa5, 0xd0, 0x87, 0x28, 0xb2, 0x14, 0xea, 0xb3, 0x89, 0x1a, 0x1b, 0x71, 0x14, 0x42, 0x47, 0xc7,
+	0x09, 0x72, 0xda, 0xff, 0xad, 0x09, 0xa4, 0xf8, 0x69, 0x9b, 0x14, 0x17, 0x36, 0xe7, 0xc8, 0x45,
+	0xcc, 0xd2, 0xcc, 0x2d, 0x4b, 0x5a, 0x30, 0xc9, 0x42, 0x96, 0xd8, 0xa7, 0x93, 0xd3, 0xca, 0xe5,
+	0xd9, 0x54, 0x27, 0xcd, 0xbc, 0x6c, 0x4b, 0x29, 0x7e, 0xca, 0x22, 0x3c, 0x3d, 0xb1, 0xc9, 0xb1,
+	0x14, 0x79, 0x04, 0xcd, 0x90, 0xa5, 0xe7, 0xf1, 0xc8, 0xdd, 0xe8, 0xd5, 0x0f, 0x3b, 0x77, 0x3f,
+	0xbe, 0x63, 0xcb, 0xf8, 0xb2, 0x45, 0x77, 0xfa, 0x5a, 0xf0, 0xeb, 0x54, 0xf2, 0x45, 0x60, 0xb5,
+	0x94, 0x95, 0x71, 0x3a, 0x64, 0xb3, 0xd4, 0x24, 0x70, 0x23, 0xc8, 0x48, 0x65, 0x25, 0x9b, 0x49,
+	0x73, 0xb5, 0xa9, 0xaf, 0x72, 0x9a, 0x7c, 0x0a, 0x4d, 0x9d, 0x23, 0xe1, 0xb6, 0xf4, 0x57, 0x77,
+	0xb3, 0xaf, 0x16, 0xa2, 0x1d, 0x58, 0x11, 0xf2, 0x11, 0x6c, 0x0d, 0x17, 0x12, 0x45, 0x80, 0x21,
+	0xc6, 0x73, 0x8c, 0xdc, 0xb6, 0x0e, 0x6c, 0x99, 0xa9, 0xde, 0x99, 0x66, 0x9c, 0x61, 0x2a, 0x5d,
+	0xd0, 0x12, 0x4b, 0x06, 0xf9, 0x09, 0x76, 0x26, 0x28, 0x04, 0x1d, 0x15, 0x60, 0x3a, 0xfa, 0xd3,
+	0x47, 0x6f, 0x71, 0xf8, 0x45, 0x45, 0xc5, 0xb8, 0x7e, 0x09, 0x89, 0xbc, 0x84, 0x6e, 0xc6, 0xd3,
+	0x9f, 0xef, 0x6a, 0xe4, 0xcf, 0xde, 0x01, 0x59, 0x89, 0x1b, 0xd4, 0x12, 0x02, 0xf1, 0xa1, 0x1b,
+	0x51, 0x49, 0x87, 0x54, 0xe0, 0x59, 0xfc, 0x06, 0xdd, 0x2d, 0x9d, 0xcc, 0x12, 0x8f, 0xf4, 0xa0,
+	0x63, 0x8b, 0x45, 0x8b, 0x6c, 0x6b, 0x91, 0x22, 0x4b, 0x25, 0x27, 0x45, 0xf9, 0x9a, 0xf1, 0x0b,
+	0xf7, 0x9a, 0x79, 0x42, 0x96, 0xf4, 0xee, 0x43, 0xa7, 0x90, 0x4d, 0xd5, 0x98, 0x2e, 0x70, 0x61,
+	0xdf, 0x99, 0x3a, 0xaa, 0xa7, 0x3d, 0xa7, 0xc9, 0x0c, 0xed, 0x03, 0x33, 0xc4, 0x83, 0xda, 0x3d,
+	0xc7, 0xeb, 0xc3, 0xfe, 0xca, 0xb8, 0x5c, 0x05, 0xd2, 0x28, 0x82, 0x3c, 0x86, 0xeb, 0x97, 0x42,
+	0xf0, 0x3e, 0x00, 0xfe, 0x3e, 0xec, 0x06, 0xa8, 0x1c, 0x35, 0x6e, 0x64, 0x05, 0xfb, 0x0c, 0xf6,
+	0xca, 0xec, 0x65, 0x31, 0xd1, 0xe9, 0x34, 0x89, 0x51, 0x95, 0x6f, 0x5d, 0x45, 0xc2, 0x92, 0xea,
+	0x86, 0xa3, 0x90, 0x94, 0xab, 0x2a, 0xd6, 0x37, 0x96, 0xf4, 0x7f, 0x84, 0x1d, 0xdd, 0x26, 0x55,
+	0x6b, 0x7b, 0xa7, 0xce, 0xab, 0x7b, 0x5f, 0xad, 0xd0, 0xfb, 0x74, 0xff, 0x99, 0x30, 0x69, 0x8a,
+	0xb1, 0x15, 0x58, 0xca, 0xff, 0xd3, 0x81, 0x83, 0xe3, 0x39, 0x8d, 0x13, 0x3a, 0x4c, 0xb0, 0x3c,
+	0x22, 0xaa, 0x2d, 0x86, 0x40, 0x23, 0xa5, 0x93, 0x2c, 0x0d, 0xfa, 0xac, 0x60, 0xe9, 0x4c, 0x8e,
+	0x19, 0xb7, 0x0d, 0xd8, 0x52, 0xca, 0xb8, 0xd7, 0x38, 0x14, 0xb1, 0x44, 0x5b, 0xe4, 0x19, 0xa9,
+	0xe2, 0x88, 0x13, 0x1a, 0x27, 0xb6, 0x01, 0x1b, 0x42, 0x61, 0x47, 0x28, 0x42, 0x5d, 0xb8, 0xed,
+	0x40, 0x9f, 0x97, 0x2d, 0x6d, 0xb3, 0xd2, 0xd2, 0xf4, 0xb4, 0x6c, 0x2d, 0xa7, 0xa5, 0x72, 0x62,
+	0x4f, 0xdb, 0xde, 0xe7, 0x48, 0x65, 0xcc, 0xd2, 0xc2, 0x98, 0xd6, 0x26, 0x3b, 0x2b, 0x4d, 0xae,
+	0xad, 0x33, 0xb9, 0xbe, 0xc6, 0xe4, 0xc6, 0x2a, 0x93, 0x37, 0x0a, 0x26, 0x7b, 0xd0, 0x9a, 0xa4,
+	0x38, 0x61, 0x69, 0x6c, 0x5c, 0x69, 0x05, 0x39, 0x4d, 0x6e, 0x03, 0x4c, 0xa9, 0x10, 0xd3, 0x31,
+	0xa7, 0x02, 0xed, 0x20, 0x29, 0x70, 0xfc, 0x11, 0xec, 0x57, 0x7c, 0x78, 0xe7, 0x51, 0xbd, 0x03,
+	0xf5, 0xd7, 0xf1, 0xb9, 0x35, 0x5b, 0x1d, 0x4b, 0x86, 0x18, 0xab, 0x73, 0xda, 0x4f, 0x6c, 0xb0,
+	0x02, 0x0c, 0xd9, 0x1c, 0xf9, 0x22, 0x0b, 0x56, 0x51, 0xc7, 0x29, 0xeb, 0x54, 0x8c, 0xaf, 0x55,
+	0x8d, 0x2f, 0x3e, 0xc6, 0x7a, 0x79, 0x44, 0x3e, 0x83, 0x3d, 0xfd, 0x74, 0xab, 0xa9, 0x59, 0xff,
+	0x7c, 0x0b, 0x83, 0xb8, 0x56, 0x1a, 0xc4, 0xfe, 0xaf, 0x0e, 0xec, 0x57, 0xc0, 0xfe, 0xed, 0xb5,
+	0xc1, 0x1f, 0xc0, 0xfe, 0x00, 0x53, 0xe4, 0x54, 0xa2, 0x36, 0x4d, 0x5c, 0xed, 0x60, 0x69, 0x56,
+	0x67, 0x5b, 0xa7, 0x7f, 0x04, 0x07, 0x55, 0xa0, 0x82, 0x73, 0x54, 0x8c, 0x51, 0xd8, 0x86, 0x61,
+	0x29, 0xff, 0x13, 0x20, 0xaf, 0x70, 0x38, 0x66, 0xec, 0xe2, 0xca, 0x9d, 0xd6, 0xef, 0xc1, 0xb6,
+	0x95, 0xbd, 0xbc, 0x22, 0xd6, 0xf5, 0x8a, 0xf8, 0xbb, 0x03, 0x37, 0xac, 0xc8, 0x09, 0x26, 0xb1,
+	0x79, 0x19, 0x97, 0x5a, 0x41, 0x3d, 0x7b, 0x70, 0x33, 0x9e, 0x4d, 0x7c, 0x75, 0x5c, 0xff, 0x00,
+	0x0a, 0xa9, 0x69, 0x94, 0x52, 0xe3, 0x41, 0x8b, 0x4a, 0x89, 0x93, 0xa9, 0x14, 0x3a, 0xaa, 0x1b,
+	0x41, 0x4e, 0xeb, 0x8a, 0xe3, 0x9c, 0x71, 0xdb, 0x0f, 0x0c, 0xa1, 0xab, 0x19, 0x7f, 0x31, 0xfd,
+	0xa0, 0x1e, 0xe8, 0xb3, 0xff, 0x15, 0x6c, 0xf7, 0xd9, 0x64, 0x52, 0x78, 0x0a, 0x2e, 0x6c, 0x8a,
+	0x59, 0x18, 0xa2, 0x10, 0xda, 0xe0, 0x56, 0x90, 0x91, 0x4b, 0xd4, 0x5a, 0x01, 0xf5, 0xee, 0x5f,
+	0x1d, 0x68, 0x9f, 0x3e, 0xed, 0xbf, 0xd0, 0x03, 0x92, 0x3c, 0x80, 0xf6, 0x00, 0x65, 0xdf, 0x8c,
+	0xfb, 0xbd, 0xd2, 0x2e, 0x60, 0x03, 0xe7, 0xed, 0x57, 0xb8, 0xe6, 0xbb, 0x47, 0x8e, 0xd5, 0x35,
+	0xc9, 0x5b, 0xea, 0x16, 0xd7, 0x65, 0x6f, 0xbf, 0xc2, 0xcd, 0x75, 0x8f, 0x61, 0x3b, 0xd3, 0x7d,
+	0xb2, 0xd0, 0x4f, 0xd1, 0x2d, 0x89, 0x16, 0x3a, 0xff, 0x1a, 0x10, 0xf2, 0x25, 0x74, 0xf4, 0x32,
+	0x5a, 0x35, 0xa0, 0xb8, 0xa1, 0xae, 0x37, 0xe0, 0x29, 0x6c, 0x0d, 0x50, 0x2e, 0xf7, 0x03, 0xf2,
+	0x9f, 0x55, 0x3b, 0x83, 0x01, 0xf1, 0xd6, 0xaf, 0x13, 0xe4, 0x14, 0xba, 0xc5, 0xb1, 0x47, 0xfe,
+	0x9b, 0xc9, 0xae, 0x98, 0x91, 0xde, 0xcd, 0xd5, 0x97, 0x16, 0xea, 0x19, 0x74, 0x74, 0xa1, 0x9b,
+	0xb9, 0x44, 0x6e, 0x96, 0xe2, 0x5e, 0xe9, 0x27, 0xde, 0xad, 0x35, 0xb7, 0x16, 0xeb, 0x5b, 0xe8,
+	0xda, 0x7e, 0xb7, 0x0a, 0xac, 0xd2, 0x0a, 0xaf, 0x02, 0xcb, 0x0d, 0xd3, 0x41, 0x5c, 0x62, 0xad,
+	0x6a, 0x74, 0xde, 0xad, 0x35, 0xb7, 0x16, 0x2b, 0x80, 0xeb, 0x05, 0xac, 0x33, 0xc9, 0x91, 0x4e,
+	0xfe, 0x11, 0xe2, 0xa1, 0x43, 0xbe, 0x87, 0xed, 0x72, 0x2b, 0x21, 0xb9, 0xca, 0xca, 0x5e, 0xe5,
+	0xdd, 0x5e, 0x77, 0x6d, 0x8d, 0xbc, 0x07, 0xad, 0xe3, 0x28, 0x32, 0x91, 0x5b, 0x5d, 0x14, 0x07,
+	0x39, 0xb7, 0x5c, 0x8d, 0x0f, 0xa1, 0x73, 0x82, 0x09, 0x4a, 0xfc, 0x10, 0xe5, 0xe7, 0x40, 0x06,
+	0x28, 0xcb, 0xeb, 0xc9, 0xba, 0xaa, 0xcc, 0x5d, 0x58, 0xbd, 0xcd, 0x1c, 0x39, 0xe4, 0x21, 0xb4,
+	0x06, 0x28, 0xd5, 0x4f, 0x74, 0x41, 0x6e, 0x64, 0xd2, 0x95, 0x7f, 0x04, 0xbc, 0xbd, 0xe2, 0x45,
+	0x41, 0xf9, 0x0b, 0xd8, 0x3c, 0x8e, 0x22, 0xc5, 0x24, 0xbb, 0x65, 0x91, 0xb7, 0xbb, 0xf0, 0x40,
+	0x6f, 0xb7, 0x29, 0x86, 0xf2, 0x83, 0x74, 0x4f, 0x62, 0x11, 0xb2, 0x34, 0x7d, 0x7f, 0xdd, 0xfb,
+	0x00, 0x26, 0xee, 0xef, 0xaf, 0xfa, 0x12, 0xae, 0x0d, 0x50, 0x9e, 0x20, 0x8d, 0xec, 0x38, 0x10,
+	0x24, 0x2f, 0xf8, 0xcb, 0xf3, 0xc6, 0xfb, 0x5f, 0xe5, 0xae, 0x3a, 0x3c, 0x8e, 0x1c, 0xf2, 0x48,
+	0x15, 0x9f, 0xe4, 0x0b, 0x2b, 0x41, 0x0e, 0x2a, 0x2a, 0x57, 0x59, 0xf4, 0x18, 0xb6, 0x8c, 0x33,
+	0x1f, 0x08, 0x30, 0x6c, 0xea, 0x1f, 0xa5, 0x9f, 0xff, 0x3d, 0x00, 0x9f, 0x07, 0x7b, 0x82, 0x19,
+	0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -774,15 +1709,26 @@ const _ = grpc.SupportPackageIsVersion4
 type IFCManageClient interface {
 	GetChains(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (IFCManage_GetChainsClient, error)
 	GetBlocks(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (IFCManage_GetBlocksClient, error)
+	GetBlockByHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	WatchBlocks(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (IFCManage_WatchBlocksClient, error)
+	GetNodeStatus(ctx context.Context, in *NodeStatusRequest, opts ...grpc.CallOption) (*NodeStatusResponse, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
 	CreateChain(ctx context.Context, in *ChainCreationRequest, opts ...grpc.CallOption) (*ChainCreationResponse, error)
+	RecoverChain(ctx context.Context, in *ChainRecoveryRequest, opts ...grpc.CallOption) (*ChainCreationResponse, error)
 	CreateBlock(ctx context.Context, in *BlockCreationRequest, opts ...grpc.CallOption) (*BlockCreationResponse, error)
+	CreateBlockStream(ctx context.Context, opts ...grpc.CallOption) (IFCManage_CreateBlockStreamClient, error)
+	GenerateBlocks(ctx context.Context, in *GenerateBlocksRequest, opts ...grpc.CallOption) (*GenerateBlocksResponse, error)
 	AddChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error)
 	DeleteChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error)
+	GetAvailableChains(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (IFCManage_GetAvailableChainsClient, error)
 	GetPeers(ctx context.Context, in *PeerListRequest, opts ...grpc.CallOption) (IFCManage_GetPeersClient, error)
 	AddPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error)
 	ConnectPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error)
 	DisconnPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error)
 	DeletePeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error)
+	GetDeadWebhooks(ctx context.Context, in *WebhookListRequest, opts ...grpc.CallOption) (IFCManage_GetDeadWebhooksClient, error)
+	RetryWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*CommonResponse, error)
+	DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*CommonResponse, error)
 }
 
 type iFCManageClient struct {
@@ -793,12 +1739,223 @@ func NewIFCManageClient(cc *grpc.ClientConn) IFCManageClient {
 	return &iFCManageClient{cc}
 }
 
-func (c *iFCManageClient) GetChains(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (IFCManage_GetChainsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[0], "/manage.IFCManage/GetChains", opts...)
+func (c *iFCManageClient) GetChains(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (IFCManage_GetChainsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[0], "/manage.IFCManage/GetChains", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iFCManageGetChainsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IFCManage_GetChainsClient interface {
+	Recv() (*ChainResponse, error)
+	grpc.ClientStream
+}
+
+type iFCManageGetChainsClient struct {
+	grpc.ClientStream
+}
+
+func (x *iFCManageGetChainsClient) Recv() (*ChainResponse, error) {
+	m := new(ChainResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iFCManageClient) GetBlocks(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (IFCManage_GetBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[1], "/manage.IFCManage/GetBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iFCManageGetBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IFCManage_GetBlocksClient interface {
+	Recv() (*BlockResponse, error)
+	grpc.ClientStream
+}
+
+type iFCManageGetBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *iFCManageGetBlocksClient) Recv() (*BlockResponse, error) {
+	m := new(BlockResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iFCManageClient) GetBlockByHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/GetBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) WatchBlocks(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (IFCManage_WatchBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[2], "/manage.IFCManage/WatchBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iFCManageWatchBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IFCManage_WatchBlocksClient interface {
+	Recv() (*BlockResponse, error)
+	grpc.ClientStream
+}
+
+type iFCManageWatchBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *iFCManageWatchBlocksClient) Recv() (*BlockResponse, error) {
+	m := new(BlockResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iFCManageClient) GetNodeStatus(ctx context.Context, in *NodeStatusRequest, opts ...grpc.CallOption) (*NodeStatusResponse, error) {
+	out := new(NodeStatusResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/GetNodeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) CreateChain(ctx context.Context, in *ChainCreationRequest, opts ...grpc.CallOption) (*ChainCreationResponse, error) {
+	out := new(ChainCreationResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/CreateChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) RecoverChain(ctx context.Context, in *ChainRecoveryRequest, opts ...grpc.CallOption) (*ChainCreationResponse, error) {
+	out := new(ChainCreationResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/RecoverChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) CreateBlock(ctx context.Context, in *BlockCreationRequest, opts ...grpc.CallOption) (*BlockCreationResponse, error) {
+	out := new(BlockCreationResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/CreateBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) CreateBlockStream(ctx context.Context, opts ...grpc.CallOption) (IFCManage_CreateBlockStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[3], "/manage.IFCManage/CreateBlockStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iFCManageCreateBlockStreamClient{stream}
+	return x, nil
+}
+
+type IFCManage_CreateBlockStreamClient interface {
+	Send(*BlockCreationRequest) error
+	CloseAndRecv() (*BlockCreationResponse, error)
+	grpc.ClientStream
+}
+
+type iFCManageCreateBlockStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *iFCManageCreateBlockStreamClient) Send(m *BlockCreationRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *iFCManageCreateBlockStreamClient) CloseAndRecv() (*BlockCreationResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BlockCreationResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iFCManageClient) GenerateBlocks(ctx context.Context, in *GenerateBlocksRequest, opts ...grpc.CallOption) (*GenerateBlocksResponse, error) {
+	out := new(GenerateBlocksResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/GenerateBlocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) AddChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+	out := new(CommonResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/AddChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) DeleteChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+	out := new(CommonResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/DeleteChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iFCManageClient) GetAvailableChains(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (IFCManage_GetAvailableChainsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[4], "/manage.IFCManage/GetAvailableChains", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &iFCManageGetChainsClient{stream}
+	x := &iFCManageGetAvailableChainsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -808,29 +1965,29 @@ func (c *iFCManageClient) GetChains(ctx context.Context, in *ChainRequest, opts
 	return x, nil
 }
 
-type IFCManage_GetChainsClient interface {
-	Recv() (*ChainResponse, error)
+type IFCManage_GetAvailableChainsClient interface {
+	Recv() (*AvailableChainResponse, error)
 	grpc.ClientStream
 }
 
-type iFCManageGetChainsClient struct {
+type iFCManageGetAvailableChainsClient struct {
 	grpc.ClientStream
 }
 
-func (x *iFCManageGetChainsClient) Recv() (*ChainResponse, error) {
-	m := new(ChainResponse)
+func (x *iFCManageGetAvailableChainsClient) Recv() (*AvailableChainResponse, error) {
+	m := new(AvailableChainResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *iFCManageClient) GetBlocks(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (IFCManage_GetBlocksClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[1], "/manage.IFCManage/GetBlocks", opts...)
+func (c *iFCManageClient) GetPeers(ctx context.Context, in *PeerListRequest, opts ...grpc.CallOption) (IFCManage_GetPeersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[5], "/manage.IFCManage/GetPeers", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &iFCManageGetBlocksClient{stream}
+	x := &iFCManageGetPeersClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -840,65 +1997,65 @@ func (c *iFCManageClient) GetBlocks(ctx context.Context, in *BlockRequest, opts
 	return x, nil
 }
 
-type IFCManage_GetBlocksClient interface {
-	Recv() (*BlockResponse, error)
+type IFCManage_GetPeersClient interface {
+	Recv() (*PeerResponse, error)
 	grpc.ClientStream
 }
 
-type iFCManageGetBlocksClient struct {
+type iFCManageGetPeersClient struct {
 	grpc.ClientStream
 }
 
-func (x *iFCManageGetBlocksClient) Recv() (*BlockResponse, error) {
-	m := new(BlockResponse)
+func (x *iFCManageGetPeersClient) Recv() (*PeerResponse, error) {
+	m := new(PeerResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *iFCManageClient) CreateChain(ctx context.Context, in *ChainCreationRequest, opts ...grpc.CallOption) (*ChainCreationResponse, error) {
-	out := new(ChainCreationResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/CreateChain", in, out, opts...)
+func (c *iFCManageClient) AddPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+	out := new(CommonResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/AddPeer", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *iFCManageClient) CreateBlock(ctx context.Context, in *BlockCreationRequest, opts ...grpc.CallOption) (*BlockCreationResponse, error) {
-	out := new(BlockCreationResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/CreateBlock", in, out, opts...)
+func (c *iFCManageClient) ConnectPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+	out := new(CommonResponse)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/ConnectPeer", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *iFCManageClient) AddChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+func (c *iFCManageClient) DisconnPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
 	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/AddChain", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/DisconnPeer", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *iFCManageClient) DeleteChain(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+func (c *iFCManageClient) DeletePeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
 	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/DeleteChain", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/DeletePeer", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *iFCManageClient) GetPeers(ctx context.Context, in *PeerListRequest, opts ...grpc.CallOption) (IFCManage_GetPeersClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[2], "/manage.IFCManage/GetPeers", opts...)
+func (c *iFCManageClient) GetDeadWebhooks(ctx context.Context, in *WebhookListRequest, opts ...grpc.CallOption) (IFCManage_GetDeadWebhooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IFCManage_serviceDesc.Streams[6], "/manage.IFCManage/GetDeadWebhooks", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &iFCManageGetPeersClient{stream}
+	x := &iFCManageGetDeadWebhooksClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -908,53 +2065,35 @@ func (c *iFCManageClient) GetPeers(ctx context.Context, in *PeerListRequest, opt
 	return x, nil
 }
 
-type IFCManage_GetPeersClient interface {
-	Recv() (*PeerResponse, error)
+type IFCManage_GetDeadWebhooksClient interface {
+	Recv() (*WebhookDeliveryResponse, error)
 	grpc.ClientStream
 }
 
-type iFCManageGetPeersClient struct {
+type iFCManageGetDeadWebhooksClient struct {
 	grpc.ClientStream
 }
 
-func (x *iFCManageGetPeersClient) Recv() (*PeerResponse, error) {
-	m := new(PeerResponse)
+func (x *iFCManageGetDeadWebhooksClient) Recv() (*WebhookDeliveryResponse, error) {
+	m := new(WebhookDeliveryResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *iFCManageClient) AddPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
-	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/AddPeer", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *iFCManageClient) ConnectPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
-	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/ConnectPeer", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *iFCManageClient) DisconnPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+func (c *iFCManageClient) RetryWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
 	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/DisconnPeer", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/RetryWebhook", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *iFCManageClient) DeletePeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+func (c *iFCManageClient) DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
 	out := new(CommonResponse)
-	err := c.cc.Invoke(ctx, "/manage.IFCManage/DeletePeer", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manage.IFCManage/DeleteWebhook", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -965,15 +2104,26 @@ func (c *iFCManageClient) DeletePeer(ctx context.Context, in *PeerRequest, opts
 type IFCManageServer interface {
 	GetChains(*ChainRequest, IFCManage_GetChainsServer) error
 	GetBlocks(*BlockRequest, IFCManage_GetBlocksServer) error
+	GetBlockByHash(context.Context, *BlockHashRequest) (*BlockResponse, error)
+	WatchBlocks(*WatchRequest, IFCManage_WatchBlocksServer) error
+	GetNodeStatus(context.Context, *NodeStatusRequest) (*NodeStatusResponse, error)
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
 	CreateChain(context.Context, *ChainCreationRequest) (*ChainCreationResponse, error)
+	RecoverChain(context.Context, *ChainRecoveryRequest) (*ChainCreationResponse, error)
 	CreateBlock(context.Context, *BlockCreationRequest) (*BlockCreationResponse, error)
+	CreateBlockStream(IFCManage_CreateBlockStreamServer) error
+	GenerateBlocks(context.Context, *GenerateBlocksRequest) (*GenerateBlocksResponse, error)
 	AddChain(context.Context, *ChainRequest) (*CommonResponse, error)
 	DeleteChain(context.Context, *ChainRequest) (*CommonResponse, error)
+	GetAvailableChains(*ChainRequest, IFCManage_GetAvailableChainsServer) error
 	GetPeers(*PeerListRequest, IFCManage_GetPeersServer) error
 	AddPeer(context.Context, *PeerRequest) (*CommonResponse, error)
 	ConnectPeer(context.Context, *PeerRequest) (*CommonResponse, error)
 	DisconnPeer(context.Context, *PeerRequest) (*CommonResponse, error)
 	DeletePeer(context.Context, *PeerRequest) (*CommonResponse, error)
+	GetDeadWebhooks(*WebhookListRequest, IFCManage_GetDeadWebhooksServer) error
+	RetryWebhook(context.Context, *WebhookRequest) (*CommonResponse, error)
+	DeleteWebhook(context.Context, *WebhookRequest) (*CommonResponse, error)
 }
 
 func RegisterIFCManageServer(s *grpc.Server, srv IFCManageServer) {
@@ -1022,6 +2172,81 @@ func (x *iFCManageGetBlocksServer) Send(m *BlockResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _IFCManage_GetBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).GetBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/GetBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).GetBlockByHash(ctx, req.(*BlockHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IFCManage_WatchBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IFCManageServer).WatchBlocks(m, &iFCManageWatchBlocksServer{stream})
+}
+
+type IFCManage_WatchBlocksServer interface {
+	Send(*BlockResponse) error
+	grpc.ServerStream
+}
+
+type iFCManageWatchBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *iFCManageWatchBlocksServer) Send(m *BlockResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _IFCManage_GetNodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).GetNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/GetNodeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).GetNodeStatus(ctx, req.(*NodeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IFCManage_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IFCManage_CreateChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChainCreationRequest)
 	if err := dec(in); err != nil {
@@ -1040,6 +2265,24 @@ func _IFCManage_CreateChain_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IFCManage_RecoverChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChainRecoveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).RecoverChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/RecoverChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).RecoverChain(ctx, req.(*ChainRecoveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IFCManage_CreateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockCreationRequest)
 	if err := dec(in); err != nil {
@@ -1058,6 +2301,50 @@ func _IFCManage_CreateBlock_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IFCManage_CreateBlockStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IFCManageServer).CreateBlockStream(&iFCManageCreateBlockStreamServer{stream})
+}
+
+type IFCManage_CreateBlockStreamServer interface {
+	SendAndClose(*BlockCreationResponse) error
+	Recv() (*BlockCreationRequest, error)
+	grpc.ServerStream
+}
+
+type iFCManageCreateBlockStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *iFCManageCreateBlockStreamServer) SendAndClose(m *BlockCreationResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *iFCManageCreateBlockStreamServer) Recv() (*BlockCreationRequest, error) {
+	m := new(BlockCreationRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _IFCManage_GenerateBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).GenerateBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/GenerateBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).GenerateBlocks(ctx, req.(*GenerateBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IFCManage_AddChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChainRequest)
 	if err := dec(in); err != nil {
@@ -1094,6 +2381,27 @@ func _IFCManage_DeleteChain_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IFCManage_GetAvailableChains_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChainRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IFCManageServer).GetAvailableChains(m, &iFCManageGetAvailableChainsServer{stream})
+}
+
+type IFCManage_GetAvailableChainsServer interface {
+	Send(*AvailableChainResponse) error
+	grpc.ServerStream
+}
+
+type iFCManageGetAvailableChainsServer struct {
+	grpc.ServerStream
+}
+
+func (x *iFCManageGetAvailableChainsServer) Send(m *AvailableChainResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _IFCManage_GetPeers_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(PeerListRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1187,18 +2495,95 @@ func _IFCManage_DeletePeer_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IFCManage_GetDeadWebhooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WebhookListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IFCManageServer).GetDeadWebhooks(m, &iFCManageGetDeadWebhooksServer{stream})
+}
+
+type IFCManage_GetDeadWebhooksServer interface {
+	Send(*WebhookDeliveryResponse) error
+	grpc.ServerStream
+}
+
+type iFCManageGetDeadWebhooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *iFCManageGetDeadWebhooksServer) Send(m *WebhookDeliveryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _IFCManage_RetryWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).RetryWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/RetryWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).RetryWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IFCManage_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IFCManageServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/manage.IFCManage/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IFCManageServer).DeleteWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _IFCManage_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "manage.IFCManage",
 	HandlerType: (*IFCManageServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlockByHash",
+			Handler:    _IFCManage_GetBlockByHash_Handler,
+		},
+		{
+			MethodName: "GetNodeStatus",
+			Handler:    _IFCManage_GetNodeStatus_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _IFCManage_ReloadConfig_Handler,
+		},
 		{
 			MethodName: "CreateChain",
 			Handler:    _IFCManage_CreateChain_Handler,
 		},
+		{
+			MethodName: "RecoverChain",
+			Handler:    _IFCManage_RecoverChain_Handler,
+		},
 		{
 			MethodName: "CreateBlock",
 			Handler:    _IFCManage_CreateBlock_Handler,
 		},
+		{
+			MethodName: "GenerateBlocks",
+			Handler:    _IFCManage_GenerateBlocks_Handler,
+		},
 		{
 			MethodName: "AddChain",
 			Handler:    _IFCManage_AddChain_Handler,
@@ -1223,6 +2608,14 @@ var _IFCManage_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeletePeer",
 			Handler:    _IFCManage_DeletePeer_Handler,
 		},
+		{
+			MethodName: "RetryWebhook",
+			Handler:    _IFCManage_RetryWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _IFCManage_DeleteWebhook_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1235,11 +2628,31 @@ var _IFCManage_serviceDesc = grpc.ServiceDesc{
 			Handler:       _IFCManage_GetBlocks_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchBlocks",
+			Handler:       _IFCManage_WatchBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "CreateBlockStream",
+			Handler:       _IFCManage_CreateBlockStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetAvailableChains",
+			Handler:       _IFCManage_GetAvailableChains_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "GetPeers",
 			Handler:       _IFCManage_GetPeers_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetDeadWebhooks",
+			Handler:       _IFCManage_GetDeadWebhooks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "manage.proto",
 }
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/services"
+	"github.com/Infnote/infnotechain/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// Methods callable with read scope, any other method requires admin scope
+var readMethods = map[string]bool{
+	"/manage.IFCManage/GetChains":          true,
+	"/manage.IFCManage/GetBlocks":          true,
+	"/manage.IFCManage/GetBlockByHash":     true,
+	"/manage.IFCManage/WatchBlocks":        true,
+	"/manage.IFCManage/GetNodeStatus":      true,
+	"/manage.IFCManage/GetAvailableChains": true,
+	"/manage.IFCManage/GetPeers":           true,
+	"/manage.IFCManage/GetDeadWebhooks":    true,
+}
+
+// Token of cli sent as 'authorization' metadata of every call
+type tokenCredential struct {
+	token  string
+	secure bool
+}
+
+func (t tokenCredential) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredential) RequireTransportSecurity() bool {
+	return t.secure
+}
+
+func authorize(ctx context.Context, method string) error {
+	scope := services.ScopeAdmin
+	if readMethods[method] {
+		scope = services.ScopeRead
+	}
+
+	credential := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			credential = values[0]
+		} else if values := md.Get("x-api-key"); len(values) > 0 {
+			credential = values[0]
+		}
+	}
+
+	if err := services.Authorize(credential, scope); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func serverOptions() ([]grpc.ServerOption, bool, error) {
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryAuth),
+		grpc.StreamInterceptor(streamAuth),
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.MaxSendMsgSize(maxMessageSize),
+	}
+
+	config, err := services.ServerTLSConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	if config != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(config)))
+	}
+	return options, config != nil, nil
+}
+
+// Credentials of cli from 'manage.client'
+func dialOptions() ([]grpc.DialOption, error) {
+	options := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxMessageSize),
+			grpc.MaxCallSendMsgSize(maxMessageSize)),
+	}
+
+	config, err := services.ClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		options = append(options, grpc.WithInsecure())
+	}
+
+	if token := utils.GetString("manage.client.token"); len(token) > 0 {
+		options = append(options, grpc.WithPerRPCCredentials(tokenCredential{token, config != nil}))
+	}
+	return options, nil
+}
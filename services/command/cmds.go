@@ -1,14 +1,22 @@
 package command
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/Infnote/infnotechain/database"
+	"github.com/Infnote/infnotechain/protocol"
 	"github.com/Infnote/infnotechain/services"
 	"github.com/Infnote/infnotechain/utils"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 )
 
 // - Direct Commands
@@ -17,8 +25,9 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version of Infnote Chain",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Infnote Chain v0.2")
-		fmt.Println("Protocol v1.1")
+		fmt.Printf("Infnote Chain v%v\n", services.Version)
+		fmt.Printf("Protocol v%v\n", protocol.ProtocolVersion)
+		fmt.Printf("Network %v\n", utils.Network())
 	},
 }
 
@@ -35,7 +44,7 @@ var runCmd = &cobra.Command{
 	Short: "Start Infnote Chain service",
 	Run: func(cmd *cobra.Command, args []string) {
 		if cmd.Flag("debug").Value.String() == "true" {
-			viper.Set("log.level", "debug")
+			utils.Set("log.level", "debug")
 		}
 
 		if cmd.Flag("filelog").Value.String() == "true" {
@@ -45,8 +54,7 @@ var runCmd = &cobra.Command{
 		}
 
 		if cmd.Flag("foreground").Value.String() == "true" {
-			go RunManageServer()
-			services.PeerService()
+			runServices()
 		} else {
 			if utils.CheckProcessAlive() {
 				fmt.Println("Infnote Chain service is already running")
@@ -61,6 +69,54 @@ var runCmd = &cobra.Command{
 	},
 }
 
+// Run all services until SIGINT or SIGTERM, a second signal exits immediately.
+// Config file is reloaded on SIGHUP.
+func runServices() {
+	database.Migrate()
+	database.Register()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	services.StartWriter(ctx, RunManageServer)
+	services.StartWriter(ctx, services.APIService)
+	services.Start(ctx, services.MetricsService)
+	services.Start(ctx, services.PeerService)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range signals {
+		if sig == syscall.SIGHUP {
+			if _, err := utils.ReloadConfig(); err != nil {
+				utils.L.Warningf("failed to reload config: %v", err)
+			}
+			continue
+		}
+		utils.L.Infof("%v received, shutting down", sig)
+		break
+	}
+	cancel()
+
+	go func() {
+		<-signals
+		utils.L.Warning("exit without shutting down")
+		os.Exit(1)
+	}()
+
+	if !services.Wait(services.ShutdownTimeout() * 2) {
+		utils.L.Warning("services are not stopped in time")
+	}
+	database.Close()
+	utils.RemovePIDFile()
+	utils.L.Info("service stopped")
+}
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload config file of the service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ReloadConfig()
+	},
+}
+
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop Infnote Chain service",
@@ -78,6 +134,17 @@ var cliCmd = &cobra.Command{
 }
 
 // - CLI Commands
+//
+// Commands of the running service, available in cli and as direct
+// commands like 'ifc blocks --chain [chain id] 0 10'
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print status and statistics of the service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return GetNodeStatus()
+	},
+}
 
 var peersCmd = &cobra.Command{
 	Use:   "peers",
@@ -91,24 +158,32 @@ var peersCmd = &cobra.Command{
 		}
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		count := 0
 		if len(args) > 0 {
 			count, _ = strconv.Atoi(args[0])
 		}
-		GetPeers(int32(count))
+		return GetPeers(int32(count))
 	},
 }
 
 var chainsCmd = &cobra.Command{
 	Use:   "chains",
 	Short: "Print accepted chains detail",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		id := ""
 		if len(args) > 0 {
-			id = args[1]
+			id = args[0]
 		}
-		GetChains(id)
+		return GetChains(id)
+	},
+}
+
+var availableCmd = &cobra.Command{
+	Use:   "available",
+	Short: "Print chains discovered from peers but not added",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return GetAvailableChains()
 	},
 }
 
@@ -116,10 +191,6 @@ var blocksCmd = &cobra.Command{
 	Use:   "blocks",
 	Short: "Print blocks detail",
 	Args: func(cmd *cobra.Command, args []string) error {
-		if chainContext == nil {
-			return errors.New("chain context not set")
-		}
-
 		if len(args) < 2 {
 			return errors.New("usage: blocks [from] [to]")
 		}
@@ -140,10 +211,14 @@ var blocksCmd = &cobra.Command{
 
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
+		}
 		from, _ := strconv.Atoi(args[0])
 		to, _ := strconv.Atoi(args[1])
-		GetBlocks(chainContext.ID, uint64(from), uint64(to))
+		return GetBlocks(id, uint64(from), uint64(to))
 	},
 }
 
@@ -151,83 +226,223 @@ var dumpCmd = &cobra.Command{
 	Use: "dump",
 	Short: "Print a all details of a block with height",
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if chainContext == nil {
-			fmt.Println("chain context not set")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
 		}
 
 		height, err := strconv.Atoi(args[0])
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 
-		DumpBlock(chainContext.ID, uint64(height))
+		return DumpBlock(id, uint64(height))
+	},
+}
+
+var findCmd = &cobra.Command{
+	Use: "find",
+	Short: "Print a block with hash in current chain, or in all chains if no chain context",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := chainID(cmd)
+		remote, _ := cmd.Flags().GetBool("remote")
+		return FindBlock(id, args[0], remote)
+	},
+}
+
+var tailCmd = &cobra.Command{
+	Use: "tail",
+	Short: "Print last blocks of current chain and follow new blocks, Ctrl-C to stop",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			_, err := strconv.ParseUint(args[0], 10, 64)
+			return err
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
+		}
+		last := uint64(10)
+		if len(args) > 0 {
+			last, _ = strconv.ParseUint(args[0], 10, 64)
+		}
+		return TailBlocks(id, last)
 	},
 }
 
 var useChainCmd = &cobra.Command{
 	Use: "use",
 	Short: "Select a chain as current context",
+	Hidden: true,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !interactive {
+			return errors.New("'use' is only available in cli, use --chain instead")
+		}
 		ref, err := strconv.Atoi(args[0])
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 		chain, ok := cachedChains[int64(ref)]
 		if !ok {
-			fmt.Println("unknown ref of chain, run 'chains' first")
-			return
+			return errors.New("unknown ref of chain, run 'chains' first")
 		}
 		chainContext = chain
 		fmt.Printf("switch to chain '%v'\n", chain.ID)
+		return nil
 	},
 }
 
 var createChainCmd = &cobra.Command{
 	Use:   "createchain",
 	Short: "create a new chain with genesis block payload as chain info",
-	Run: func(cmd *cobra.Command, args []string) {
-		CreateChain(
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return CreateChain(
 			cmd.Flag("name").Value.String(),
 			cmd.Flag("author").Value.String(),
 			cmd.Flag("website").Value.String(),
 			cmd.Flag("email").Value.String(),
-			cmd.Flag("desc").Value.String())
+			cmd.Flag("desc").Value.String(),
+			cmd.Flag("mnemonic").Value.String() == "true",
+			cmd.Flag("passphrase").Value.String())
+	},
+}
+
+var recoverChainCmd = &cobra.Command{
+	Use:   "recoverchain",
+	Short: "recover an owned chain from mnemonic words, words are read from stdin if not given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mnemonic := strings.Join(args, " ")
+		if len(args) == 0 {
+			if interactive {
+				return errors.New("usage: recoverchain [words]")
+			}
+			words, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			mnemonic = string(words)
+		}
+		return RecoverChain(
+			mnemonic,
+			cmd.Flag("passphrase").Value.String(),
+			cmd.Flag("chain").Value.String())
 	},
 }
 
 var createBlockCmd = &cobra.Command{
 	Use:   "createblock",
-	Short: "create a new block with payload",
+	Short: "create a new block with payload, from an argument, a file or stdin",
 	Args: func(cmd *cobra.Command, args []string) error {
-		if chainContext == nil {
-			return errors.New("chain context not set")
+		file, _ := cmd.Flags().GetString("file")
+		stdin, _ := cmd.Flags().GetBool("stdin")
+		isJSON, _ := cmd.Flags().GetBool("json")
+		if len(file) > 0 && stdin {
+			return errors.New("--file and --stdin can not be used together")
+		}
+		if len(file) > 0 || stdin {
+			if len(args) > 0 {
+				return errors.New("payload argument can not be used with --file or --stdin")
+			}
+			return nil
+		}
+		if len(args) > 0 && (args[0] == "string" || args[0] == "base64") {
+			// base64 has no spaces, words of a string are joined
+			if len(args) < 2 || (args[0] == "base64" && len(args) != 2) {
+				return errCreateBlockUsage
+			}
+			return nil
+		}
+		if isJSON && len(args) > 0 {
+			return nil
 		}
 		if len(args) < 2 {
-			return errors.New("usage: createblock [string|base64] [payload]")
+			return errCreateBlockUsage
+		}
+		return errors.New("only support string or base64")
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
+		}
+		payload, err := blockPayload(cmd, args)
+		if err != nil {
+			return err
 		}
-		if args[0] != "string" && args[0] != "base64" {
-			return errors.New("only support string or base64")
+		return CreateBlock(id, payload)
+	},
+}
+
+var errCreateBlockUsage = errors.New("usage: createblock [string|base64] [payload], " +
+	"createblock --json [payload] or createblock --file [path]|--stdin")
+
+// Payload of 'createblock', arguments split by spaces in cli are joined.
+// JSON payload is validated with '--json'.
+func blockPayload(cmd *cobra.Command, args []string) ([]byte, error) {
+	file, _ := cmd.Flags().GetString("file")
+	stdin, _ := cmd.Flags().GetBool("stdin")
+	isJSON, _ := cmd.Flags().GetBool("json")
+
+	var payload []byte
+	var err error
+	switch {
+	case len(file) > 0:
+		payload, err = ioutil.ReadFile(file)
+	case stdin:
+		if interactive {
+			return nil, errors.New("--stdin is not available in cli, use --file instead")
+		}
+		payload, err = ioutil.ReadAll(os.Stdin)
+	case len(args) == 0:
+		return nil, errCreateBlockUsage
+	case args[0] == "base64":
+		if len(args) != 2 {
+			return nil, errCreateBlockUsage
+		}
+		payload, err = base64.StdEncoding.DecodeString(args[1])
+	case args[0] == "string":
+		payload = []byte(strings.Join(args[1:], " "))
+	default:
+		payload = []byte(strings.Join(args, " "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 {
+		return nil, errors.New("payload is empty")
+	}
+	if isJSON && !json.Valid(payload) {
+		return nil, errors.New("payload is not valid JSON")
+	}
+	return payload, nil
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "generate blocks with random payloads, only on regtest",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("usage: generate [count]")
 		}
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		var payload []byte
-		if args[0] == "string" {
-			payload = []byte(args[1])
-		} else if args[0] == "base64" {
-			b, err := base64.StdEncoding.DecodeString(args[1])
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
-			payload = b
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
+		}
+		count, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
 		}
-		CreateBlock(chainContext.ID, payload)
+		return GenerateBlocks(id, count)
 	},
 }
 
@@ -235,22 +450,32 @@ var addChainCmd = &cobra.Command{
 	Use: "addchain",
 	Short: "add an exist chain",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		AddChain(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return AddChain(args[0])
 	},
 }
 
 var delChainCmd = &cobra.Command{
 	Use: "delchain",
-	Short: "delete a chain",
+	Short: "delete a chain by ref or chain ID",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		ref, err := strconv.Atoi(args[0])
-		if err != nil {
-			fmt.Println(err)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		if ref, err := strconv.Atoi(args[0]); err == nil {
+			// refs are not cached yet in direct commands
+			if _, ok := cachedChains[int64(ref)]; !ok {
+				if _, err := fetchChains(""); err != nil {
+					return err
+				}
+			}
+			chain, ok := cachedChains[int64(ref)]
+			if !ok {
+				return errors.New("unknown ref of chain, run 'chains' first")
+			}
+			id = chain.ID
 		}
-		DeleteChain(int64(ref))
+		yes, _ := cmd.Flags().GetBool("yes")
+		return DeleteChain(id, yes)
 	},
 }
 
@@ -258,8 +483,8 @@ var addPeerCmd = &cobra.Command{
 	Use: "addpeer",
 	Short: "add a peer to peer list",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		AddPeer(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return AddPeer(args[0])
 	},
 }
 
@@ -267,8 +492,8 @@ var delPeerCmd = &cobra.Command{
 	Use: "delpeer",
 	Short: "delete a peer",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		DeletePeer(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return DeletePeer(args[0])
 	},
 }
 
@@ -276,8 +501,8 @@ var connectCmd = &cobra.Command{
 	Use: "connect",
 	Short: "connect to a peer without saving",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		ConnectPeer(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ConnectPeer(args[0])
 	},
 }
 
@@ -285,12 +510,129 @@ var disconnectCmd = &cobra.Command{
 	Use: "disconnect",
 	Short: "disconnect to a peer",
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		DisconnPeer(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return DisconnPeer(args[0])
 	},
 }
 
+var deadHooksCmd = &cobra.Command{
+	Use: "deadhooks",
+	Short: "print webhook deliveries failed after all retries",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			_, err := strconv.Atoi(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count := 0
+		if len(args) > 0 {
+			count, _ = strconv.Atoi(args[0])
+		}
+		return GetDeadWebhooks(int32(count))
+	},
+}
+
+var retryHookCmd = &cobra.Command{
+	Use: "retryhook",
+	Short: "queue a dead webhook delivery again",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		return RetryWebhook(id)
+	},
+}
+
+var delHookCmd = &cobra.Command{
+	Use: "delhook",
+	Short: "delete a dead webhook delivery",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		return DeleteWebhook(id)
+	},
+}
+
+var clientCommands = []*cobra.Command{
+	statusCmd,
+	reloadCmd,
+	peersCmd,
+	chainsCmd,
+	availableCmd,
+	useChainCmd,
+	blocksCmd,
+	dumpCmd,
+	findCmd,
+	tailCmd,
+	createChainCmd,
+	recoverChainCmd,
+	createBlockCmd,
+	generateCmd,
+	addChainCmd,
+	delChainCmd,
+	addPeerCmd,
+	delPeerCmd,
+	connectCmd,
+	disconnectCmd,
+	deadHooksCmd,
+	retryHookCmd,
+	delHookCmd,
+	createBlock,
+}
+
+// Chain of the command from '--chain', or the chain selected by 'use' in cli
+func chainID(cmd *cobra.Command) (string, error) {
+	if id := cmd.Flag("chain").Value.String(); len(id) > 0 {
+		return id, nil
+	}
+	if chainContext != nil {
+		return chainContext.ID, nil
+	}
+	return "", errors.New("chain not set, use --chain [chain id] or 'use [ref]' in cli")
+}
+
+func isClientCommand(cmd *cobra.Command) bool {
+	for _, c := range clientCommands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
 func initDirectCommands() {
+	directCmd.PersistentFlags().StringVarP(
+		&configFlag,
+		"config",
+		"c",
+		"",
+		"Config file, default is config.yaml in IFC_HOME or the user config directory")
+	directCmd.PersistentFlags().StringVar(
+		&dataDirFlag,
+		"datadir",
+		"",
+		"Directory of data and config file, default is IFC_HOME or the user data directory")
+	directCmd.PersistentFlags().StringVarP(
+		&networkFlag,
+		"network",
+		"N",
+		utils.Mainnet,
+		"Network to join: mainnet, testnet or regtest")
+	directCmd.PersistentFlags().StringVarP(
+		&outputFlag,
+		"output",
+		"o",
+		outputTable,
+		"Output format of commands: table or json")
 	runCmd.Flags().BoolP(
 		"foreground",
 		"f",
@@ -320,21 +662,26 @@ func initCLICommands() {
 	createChainCmd.Flags().StringP("website", "w", "", "website of the chain")
 	createChainCmd.Flags().StringP("email", "e", "", "email of the chain")
 	createChainCmd.Flags().StringP("desc", "d", "", "description of the chain")
-
-	cliRootCmd.AddCommand(peersCmd)
-	cliRootCmd.AddCommand(chainsCmd)
-	cliRootCmd.AddCommand(useChainCmd)
-	cliRootCmd.AddCommand(blocksCmd)
-	cliRootCmd.AddCommand(dumpCmd)
-	cliRootCmd.AddCommand(createChainCmd)
-	cliRootCmd.AddCommand(createBlockCmd)
-	cliRootCmd.AddCommand(addChainCmd)
-	cliRootCmd.AddCommand(delChainCmd)
-	cliRootCmd.AddCommand(delChainCmd)
-	cliRootCmd.AddCommand(addPeerCmd)
-	cliRootCmd.AddCommand(delPeerCmd)
-	cliRootCmd.AddCommand(connectCmd)
-	cliRootCmd.AddCommand(disconnectCmd)
+	createChainCmd.Flags().Bool("mnemonic", false, "derive key of the chain from a new mnemonic for backup")
+	createChainCmd.Flags().String("passphrase", "", "passphrase of the mnemonic")
+	recoverChainCmd.Flags().String("passphrase", "", "passphrase of the mnemonic")
+	recoverChainCmd.Flags().String("chain", "", "chain ID the words should recover")
+	delChainCmd.Flags().BoolP("yes", "y", false, "delete without confirmation")
+	createBlockCmd.Flags().StringP("file", "f", "", "read payload from the file")
+	createBlockCmd.Flags().Bool("stdin", false, "read payload from stdin")
+	createBlockCmd.Flags().Bool("json", false, "validate payload as JSON")
+	findCmd.Flags().Bool("remote", false, "ask online peers if the block is not found locally")
+
+	for _, cmd := range []*cobra.Command{blocksCmd, dumpCmd, findCmd, tailCmd, createBlockCmd, generateCmd} {
+		cmd.Flags().String("chain", "", "chain ID, default is the chain selected by 'use' in cli")
+	}
 
 	initPerformanceCommands()
+
+	for _, cmd := range clientCommands {
+		cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			return connect()
+		}
+		directCmd.AddCommand(cmd)
+	}
 }
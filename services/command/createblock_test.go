@@ -0,0 +1,189 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"github.com/Infnote/infnotechain/services/codegen"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlockPayload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ifc-payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "payload.json")
+	if err := ioutil.WriteFile(file, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		flags   map[string]string
+		args    []string
+		payload string
+		fails   bool
+	}{
+		{"joined words", nil, []string{"hello", "world"}, "hello world", false},
+		{"string", nil, []string{"string", "hello", "world"}, "hello world", false},
+		{"base64", nil, []string{"base64", "aGVsbG8="}, "hello", false},
+		{"invalid base64", nil, []string{"base64", "!"}, "", true},
+		{"file", map[string]string{"file": file}, nil, `{"a": 1}`, false},
+		{"missing file", map[string]string{"file": filepath.Join(dir, "none")}, nil, "", true},
+		{"empty", nil, []string{"string"}, "", true},
+		{"missing base64", map[string]string{"json": "true"}, []string{"base64"}, "", true},
+		{"no argument", nil, nil, "", true},
+		{"json", map[string]string{"json": "true"}, []string{`{"a":`, `1}`}, `{"a": 1}`, false},
+		{"invalid json", map[string]string{"json": "true"}, []string{"hello"}, "", true},
+	}
+
+	for _, c := range cases {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", "", "")
+		cmd.Flags().Bool("stdin", false, "")
+		cmd.Flags().Bool("json", false, "")
+		for k, v := range c.flags {
+			if err := cmd.Flags().Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		payload, err := blockPayload(cmd, c.args)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%v: payload should be refused: %q", c.name, payload)
+			}
+			continue
+		}
+		if err != nil || string(payload) != c.payload {
+			t.Errorf("%v: unexpected payload %q: %v", c.name, payload, err)
+		}
+	}
+}
+
+func TestCreateBlockArgs(t *testing.T) {
+	cases := []struct {
+		flags map[string]string
+		args  []string
+		fails bool
+	}{
+		{nil, []string{"string", "hello"}, false},
+		{nil, []string{"string", "hello", "world"}, false},
+		{nil, []string{"base64", "aGVsbG8="}, false},
+		{nil, []string{"string"}, true},
+		{nil, []string{"base64"}, true},
+		{nil, []string{"base64", "aGVs", "bG8="}, true},
+		{nil, []string{"hello", "world"}, true},
+		{map[string]string{"json": "true"}, []string{`{"a":`, `1}`}, false},
+		{map[string]string{"json": "true"}, []string{"string"}, true},
+		{map[string]string{"json": "true"}, []string{"base64"}, true},
+		{map[string]string{"json": "true"}, []string{"base64", "eyJhIjogMX0="}, false},
+		{map[string]string{"json": "true"}, nil, true},
+		{map[string]string{"stdin": "true"}, nil, false},
+		{map[string]string{"stdin": "true"}, []string{"string", "hello"}, true},
+	}
+
+	for _, c := range cases {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", "", "")
+		cmd.Flags().Bool("stdin", false, "")
+		cmd.Flags().Bool("json", false, "")
+		for k, v := range c.flags {
+			if err := cmd.Flags().Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := createBlockCmd.Args(cmd, c.args)
+		if c.fails && err == nil {
+			t.Errorf("%q with %v should be refused", c.args, c.flags)
+		}
+		if !c.fails && err != nil {
+			t.Errorf("%q with %v: %v", c.args, c.flags, err)
+		}
+	}
+}
+
+// Requests received by the client instead of the service
+type recordingClient struct {
+	manage.IFCManageClient
+	unary  []*manage.BlockCreationRequest
+	chunks []*manage.BlockCreationRequest
+}
+
+func (c *recordingClient) CreateBlock(ctx context.Context, in *manage.BlockCreationRequest, opts ...grpc.CallOption) (*manage.BlockCreationResponse, error) {
+	c.unary = append(c.unary, in)
+	return &manage.BlockCreationResponse{}, nil
+}
+
+func (c *recordingClient) CreateBlockStream(ctx context.Context, opts ...grpc.CallOption) (manage.IFCManage_CreateBlockStreamClient, error) {
+	return &recordingStream{client: c}, nil
+}
+
+type recordingStream struct {
+	grpc.ClientStream
+	client *recordingClient
+}
+
+func (s *recordingStream) Send(m *manage.BlockCreationRequest) error {
+	s.client.chunks = append(s.client.chunks, m)
+	return nil
+}
+
+func (s *recordingStream) CloseAndRecv() (*manage.BlockCreationResponse, error) {
+	return &manage.BlockCreationResponse{}, nil
+}
+
+func TestCreateBlockChunks(t *testing.T) {
+	defer func(client manage.IFCManageClient) { IFCManageClient = client }(IFCManageClient)
+
+	cases := []struct {
+		size   int
+		chunks int
+	}{
+		{1, 0},
+		{maxMessagePayload, 0},
+		{maxMessagePayload + 1, 4},
+		{10 * payloadChunkSize, 10},
+		{10*payloadChunkSize + 1, 11},
+	}
+
+	for _, c := range cases {
+		client := &recordingClient{}
+		IFCManageClient = client
+
+		payload := bytes.Repeat([]byte{'x'}, c.size)
+		payload[len(payload)-1] = 'y'
+		if err := CreateBlock("chain", payload); err != nil {
+			t.Fatal(err)
+		}
+
+		if c.chunks == 0 {
+			if len(client.unary) != 1 || len(client.chunks) != 0 || !bytes.Equal(client.unary[0].Payload, payload) {
+				t.Errorf("payload of %v bytes should be sent in one message", c.size)
+			}
+			continue
+		}
+
+		if len(client.unary) != 0 || len(client.chunks) != c.chunks {
+			t.Errorf("payload of %v bytes is sent in %v chunks, expected %v", c.size, len(client.chunks), c.chunks)
+			continue
+		}
+		var joined []byte
+		for _, chunk := range client.chunks {
+			if len(chunk.Payload) > payloadChunkSize || chunk.ChainID != "chain" {
+				t.Errorf("unexpected chunk of %v bytes on chain %q", len(chunk.Payload), chunk.ChainID)
+			}
+			joined = append(joined, chunk.Payload...)
+		}
+		if !bytes.Equal(joined, payload) {
+			t.Errorf("chunks of %v bytes are not joined to the payload", c.size)
+		}
+	}
+}
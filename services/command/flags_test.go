@@ -0,0 +1,43 @@
+package command
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"testing"
+)
+
+// Shorthands of flags of a command and persistent flags of its parents
+// are in the same set, a duplicate one is not parsed
+func TestFlagShorthands(t *testing.T) {
+	initDirectCommands()
+	initCLICommands()
+	initKeyCommands()
+
+	var check func(cmd *cobra.Command)
+	check = func(cmd *cobra.Command) {
+		names := map[string]string{}
+		visit := func(flag *pflag.Flag) {
+			if len(flag.Shorthand) == 0 {
+				return
+			}
+			if name, ok := names[flag.Shorthand]; ok && name != flag.Name {
+				t.Errorf("'%v': shorthand -%v of --%v is used by --%v", cmd.CommandPath(), flag.Shorthand, flag.Name, name)
+			}
+			names[flag.Shorthand] = flag.Name
+		}
+		cmd.Flags().VisitAll(visit)
+		cmd.PersistentFlags().VisitAll(visit)
+		for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+			parent.PersistentFlags().VisitAll(visit)
+		}
+
+		for _, sub := range cmd.Commands() {
+			check(sub)
+		}
+	}
+	check(directCmd)
+
+	if flag := directCmd.PersistentFlags().Lookup("network"); flag == nil || flag.Shorthand != "N" {
+		t.Error("--network should have shorthand -N")
+	}
+}
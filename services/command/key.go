@@ -0,0 +1,260 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/services"
+	"github.com/mr-tron/base58"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"os"
+)
+
+// - Offline Commands
+//
+// Keys and blocks are handled locally, the service is not required.
+// Signatures are base58 encoded as signatures of blocks.
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Create keys, sign and verify messages without the service",
+}
+
+var keyNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a new key and print its WIF and address",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printKey(crypto.NewKey())
+		return nil
+	},
+}
+
+var keyAddressCmd = &cobra.Command{
+	Use:   "address [wif]",
+	Short: "Print address of a key, which is the chain ID of chains owned by the key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := crypto.FromWIF(args[0])
+		if err != nil {
+			return invalidArgument("invalid WIF: %v", err)
+		}
+		printKey(key)
+		return nil
+	},
+}
+
+var keySignCmd = &cobra.Command{
+	Use:   "sign [wif] [message]",
+	Short: "Sign a message, or the content of '--file'",
+	Args:  messageArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := crypto.FromWIF(args[0])
+		if err != nil {
+			return invalidArgument("invalid WIF: %v", err)
+		}
+		message, err := keyMessage(cmd, args[1:])
+		if err != nil {
+			return err
+		}
+
+		signature := base58.Encode(key.Sign(message))
+		if jsonOutput() {
+			printJSON(map[string]string{"address": key.ToAddress(), "signature": signature})
+			return nil
+		}
+		fmt.Println(signature)
+		return nil
+	},
+}
+
+var keyVerifyCmd = &cobra.Command{
+	Use:   "verify [address] [signature] [message]",
+	Short: "Verify a message, or the content of '--file', is signed by the address",
+	Args:  messageArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		message, err := keyMessage(cmd, args[2:])
+		if err != nil {
+			return err
+		}
+		address, err := recoverAddress(args[1], message)
+		if err != nil {
+			return err
+		}
+		if address != args[0] {
+			return invalidArgument("signature is signed by %v, not %v", address, args[0])
+		}
+
+		printDone("Verified")
+		return nil
+	},
+}
+
+var keyRecoverCmd = &cobra.Command{
+	Use:   "recover [signature] [message]",
+	Short: "Print address of the key signed a message, or the content of '--file'",
+	Args:  messageArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		message, err := keyMessage(cmd, args[1:])
+		if err != nil {
+			return err
+		}
+		address, err := recoverAddress(args[0], message)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			printJSON(map[string]string{"address": address})
+			return nil
+		}
+		fmt.Println(address)
+		return nil
+	},
+}
+
+var blockCmd = &cobra.Command{
+	Use:   "block",
+	Short: "Handle serialized blocks without the service",
+}
+
+var blockVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Verify hash and signature of a serialized block, '-' reads from stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chainID, _ := cmd.Flags().GetString("chain")
+		return VerifyBlock(args[0], chainID)
+	},
+}
+
+// Arguments before the message are required, the message
+// is the last argument unless '--file' is set
+func messageArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		if len(file) > 0 {
+			return cobra.ExactArgs(n)(cmd, args)
+		}
+		return cobra.ExactArgs(n+1)(cmd, args)
+	}
+}
+
+func keyMessage(cmd *cobra.Command, args []string) ([]byte, error) {
+	if file, _ := cmd.Flags().GetString("file"); len(file) > 0 {
+		return readInput(file)
+	}
+	return []byte(args[0]), nil
+}
+
+// Content of the file, or stdin if the file is '-'
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(file)
+}
+
+func recoverAddress(signature string, message []byte) (string, error) {
+	sig, err := base58.Decode(signature)
+	if err != nil {
+		return "", invalidArgument("invalid signature: %v", err)
+	}
+	address, err := crypto.RecoverAddress(sig, message)
+	if err != nil {
+		return "", invalidArgument("invalid signature: %v", err)
+	}
+	return address, nil
+}
+
+func invalidArgument(format string, args ...interface{}) error {
+	return &services.ManageError{Kind: services.InvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+func printKey(key *crypto.Key) {
+	if jsonOutput() {
+		printJSON(map[string]string{
+			"wif":        key.ToWIF(),
+			"address":    key.ToAddress(),
+			"public_key": base58.Encode(key.PublicKey()),
+		})
+		return
+	}
+	fmt.Printf("[WIF       ] %v\n", key.ToWIF())
+	fmt.Printf("[Address   ] %v\n", key.ToAddress())
+	fmt.Printf("[Public Key] %v\n", base58.Encode(key.PublicKey()))
+}
+
+// Block serialized as in peer messages and HTTP API, such as the
+// response of 'GET /chains/[chain id]/blocks/[height]', the result of
+// 'GET /blocks/[hash]' with the chain ID is accepted too
+func VerifyBlock(file string, chainID string) error {
+	data, err := readInput(file)
+	if err != nil {
+		return err
+	}
+
+	found := &struct {
+		ChainID string          `json:"chain_id"`
+		Block   json.RawMessage `json:"block"`
+	}{}
+	if err := json.Unmarshal(data, found); err != nil {
+		return invalidArgument("invalid block: %v", err)
+	}
+	if len(found.Block) > 0 {
+		data = found.Block
+		if len(chainID) == 0 {
+			chainID = found.ChainID
+		}
+	}
+
+	block, err := blockchain.DeserializeBlock(data)
+	if err != nil {
+		return invalidArgument("invalid block: %v", err)
+	}
+	if len(block.PrevHash) > 0 {
+		if _, err := base58.Decode(block.PrevHash); err != nil {
+			return invalidArgument("invalid prev hash: %v", err)
+		}
+	}
+
+	if hash := block.ComputeHash(); hash != block.Hash {
+		return invalidArgument("hash of block %v is not matched, computed hash is %v", block.Height, hash)
+	}
+	signer, err := recoverAddress(block.Signature, block.DataForHashing())
+	if err != nil {
+		return err
+	}
+	if len(chainID) > 0 && signer != chainID {
+		return invalidArgument("block %v is signed by %v, not chain %v", block.Height, signer, chainID)
+	}
+
+	if jsonOutput() {
+		printJSON(map[string]interface{}{"height": block.Height, "hash": block.Hash, "chain_id": signer})
+		return nil
+	}
+	fmt.Printf("[Height   ] %v\n", block.Height)
+	fmt.Printf("[Hash     ] %v\n", block.Hash)
+	fmt.Printf("[Chain ID ] %v\n", signer)
+	fmt.Println("Verified")
+	return nil
+}
+
+func initKeyCommands() {
+	for _, cmd := range []*cobra.Command{keySignCmd, keyVerifyCmd, keyRecoverCmd} {
+		cmd.Flags().StringP("file", "f", "", "read message from the file, '-' reads from stdin")
+	}
+	blockVerifyCmd.Flags().String("chain", "", "chain ID the block should belong to")
+
+	keyCmd.AddCommand(keyNewCmd)
+	keyCmd.AddCommand(keyAddressCmd)
+	keyCmd.AddCommand(keySignCmd)
+	keyCmd.AddCommand(keyVerifyCmd)
+	keyCmd.AddCommand(keyRecoverCmd)
+	blockCmd.AddCommand(blockVerifyCmd)
+
+	directCmd.AddCommand(keyCmd)
+	directCmd.AddCommand(blockCmd)
+}
@@ -4,21 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain"
-	"github.com/Infnote/infnotechain/network"
-	"github.com/Infnote/infnotechain/protocol"
 	"github.com/Infnote/infnotechain/services"
 	"github.com/Infnote/infnotechain/services/codegen"
 	"github.com/Infnote/infnotechain/utils"
+	"github.com/Infnote/infnotechain/webhook"
 	"github.com/olekukonko/tablewriter"
-	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io"
 	"io/ioutil"
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"sort"
 	"strconv"
 	"syscall"
 	"time"
@@ -38,20 +41,27 @@ var chainContext *cachedChain
 var cachedPeers = map[string]bool{}
 
 func RunDaemon(flags string) {
-	path := os.Args[0]
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		path = "/usr/local/bin/" + path
+	path, err := os.Executable()
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+
+	// child process uses the same config and data directory
+	args := []string{path, "run", "-fF" + flags}
+	if len(configFlag) > 0 {
+		args = append(args, "--config", configFlag)
 	}
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		utils.L.Fatal("please put the executable file into /usr/local/bin/")
+	if len(dataDirFlag) > 0 {
+		args = append(args, "--datadir", dataDirFlag)
 	}
+	args = append(args, "--network", utils.Network())
 
-	pid, err := syscall.ForkExec(path, []string{path, "run", "-fF" + flags}, nil)
+	pid, err := syscall.ForkExec(path, args, &syscall.ProcAttr{Env: os.Environ()})
 	if err != nil {
 		utils.L.Fatal(err)
 	}
 
-	err = ioutil.WriteFile(viper.GetString("daemon.pid"), []byte(fmt.Sprintf("%d", pid)), 0655)
+	err = ioutil.WriteFile(utils.GetString("daemon.pid"), []byte(fmt.Sprintf("%d", pid)), 0655)
 	if err != nil {
 		utils.L.Fatal(err)
 	}
@@ -59,7 +69,7 @@ func RunDaemon(flags string) {
 }
 
 func StopDaemon() {
-	b, err := ioutil.ReadFile(viper.GetString("daemon.pid"))
+	b, err := ioutil.ReadFile(utils.GetString("daemon.pid"))
 	if err != nil {
 		utils.L.Fatal(err)
 	}
@@ -74,34 +84,113 @@ func StopDaemon() {
 		utils.L.Fatal(err)
 	}
 
-	err = process.Kill()
+	err = process.Signal(syscall.SIGTERM)
 	if err != nil {
 		utils.L.Fatal(err)
 	}
 
-	fmt.Printf("[PID: %v] service stopped\n", pid)
+	// service exits after closing peers and the database,
+	// killed if it does not stop in time
+	fmt.Printf("[PID: %v] stopping service...\n", pid)
+	deadline := time.Now().Add(services.ShutdownTimeout()*2 + 5*time.Second)
+	for time.Now().Before(deadline) {
+		if process.Signal(syscall.Signal(0)) != nil {
+			fmt.Printf("[PID: %v] service stopped\n", pid)
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	if err := process.Kill(); err != nil {
+		utils.L.Fatal(err)
+	}
+	_ = os.Remove(utils.GetString("daemon.pid"))
+	fmt.Printf("[PID: %v] service is not stopped in time, killed\n", pid)
 }
 
-func RunManageServer() {
+func RunManageServer(ctx context.Context) {
+	host := utils.GetString("manage.host")
 	conn, err := net.Listen(
 		"tcp",
 		fmt.Sprintf(
 			"%v:%v",
-			viper.GetString("manage.host"),
-			viper.GetString("manage.port")))
+			host,
+			utils.GetString("manage.port")))
 
 	if err != nil {
 		utils.L.Fatal(err)
 	}
 
-	server := grpc.NewServer()
+	options, secure, err := serverOptions()
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	services.WarnIfExposed("manage", host, secure)
+
+	server := grpc.NewServer(options...)
 	manage.RegisterIFCManageServer(server, &ManageServer{})
 
+	// streaming calls like WatchBlocks never finish by themselves
+	stopped := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		graceful := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(graceful)
+		}()
+		select {
+		case <-graceful:
+		case <-time.After(services.ShutdownTimeout()):
+			server.Stop()
+		}
+		close(stopped)
+	}()
+
 	utils.L.Info("manage service start")
 	err = server.Serve(conn)
 	if err != nil {
 		utils.L.Fatal(err)
 	}
+	// returns after calls like CreateBlock finished
+	<-stopped
+}
+
+func (*ManageServer) GetNodeStatus(ctx context.Context, request *manage.NodeStatusRequest) (*manage.NodeStatusResponse, error) {
+	status := services.GetNodeStatus()
+	response := &manage.NodeStatusResponse{
+		Version:          status.Version,
+		Protocol:         status.Protocol,
+		Network:          status.Network,
+		Uptime:           int64(status.Uptime.Seconds()),
+		NodeID:           status.NodeID,
+		Config:           status.Config,
+		Inbound:          int32(status.Inbound),
+		Outbound:         int32(status.Outbound),
+		BytesReceived:    status.BytesReceived,
+		BytesSent:        status.BytesSent,
+		MessagesReceived: status.MessagesReceived,
+		MessagesSent:     status.MessagesSent,
+		DatabaseSize:     status.DatabaseSize,
+		PayloadSize:      status.PayloadSize,
+	}
+	for _, chain := range status.Chains {
+		response.Chains = append(response.Chains, &manage.ChainStatus{
+			Id:       chain.ID,
+			Count:    chain.Count,
+			Best:     chain.BestKnown,
+			Progress: chain.Progress,
+		})
+	}
+	return response, nil
+}
+
+func (*ManageServer) ReloadConfig(ctx context.Context, request *manage.ReloadConfigRequest) (*manage.ReloadConfigResponse, error) {
+	changes, err := utils.ReloadConfig()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &manage.ReloadConfigResponse{Applied: changes.Applied, Restart: changes.Restart}, nil
 }
 
 func (*ManageServer) GetChains(request *manage.ChainRequest, stream manage.IFCManage_GetChainsServer) error {
@@ -118,7 +207,11 @@ func (*ManageServer) GetChains(request *manage.ChainRequest, stream manage.IFCMa
 				return err
 			}
 		}
-	} else if chain := blockchain.LoadChain(request.Id); chain != nil {
+	} else {
+		chain, err := services.GetChain(request.Id)
+		if err != nil {
+			return statusError(err)
+		}
 		if err := send(chain); err != nil {
 			return err
 		}
@@ -127,38 +220,82 @@ func (*ManageServer) GetChains(request *manage.ChainRequest, stream manage.IFCMa
 }
 
 func (*ManageServer) GetBlocks(request *manage.BlockRequest, stream manage.IFCManage_GetBlocksServer) error {
-	chain := blockchain.LoadChain(request.ChainID)
-	if chain == nil {
-		return nil
-	}
-	if request.From > request.To {
-		return nil
+	blocks, err := services.GetBlocks(stream.Context(), request.ChainID, request.From, request.To)
+	if err != nil {
+		return statusError(err)
 	}
-	for _, block := range chain.GetBlocks(request.From, request.To) {
-		err := stream.Send(&manage.BlockResponse{
-			Height:    block.Height,
-			Time:      block.Time,
-			PrevHash:  block.PrevHash,
-			Hash:      block.Hash,
-			Signature: block.Signature,
-			Payload:   block.Payload,
-		})
-		if err != nil {
+	for _, block := range blocks {
+		if err := stream.Send(blockResponse(block, request.ChainID)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func blockResponse(block *blockchain.Block, chainID string) *manage.BlockResponse {
+	return &manage.BlockResponse{
+		Height:    block.Height,
+		Time:      block.Time,
+		PrevHash:  block.PrevHash,
+		Hash:      block.Hash,
+		Signature: block.Signature,
+		Payload:   block.Payload,
+		ChainID:   chainID,
+	}
+}
+
+func (*ManageServer) GetBlockByHash(ctx context.Context, request *manage.BlockHashRequest) (*manage.BlockResponse, error) {
+	block, err := services.GetBlockByHash(ctx, request.ChainID, request.Hash, request.Remote)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return blockResponse(block, block.ChainID()), nil
+}
+
+func (*ManageServer) WatchBlocks(request *manage.WatchRequest, stream manage.IFCManage_WatchBlocksServer) error {
+	err := services.WatchBlocks(stream.Context(), request.ChainID, request.Replay, request.From, func(block *blockchain.Block) error {
+		return stream.Send(blockResponse(block, block.ChainID()))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
 func (*ManageServer) CreateChain(ctx context.Context, request *manage.ChainCreationRequest) (*manage.ChainCreationResponse, error) {
-	payload, _ := json.Marshal(map[string]string{
+	metadata := map[string]string{
 		"name":    request.Name,
 		"author":  request.Author,
 		"website": request.Website,
 		"email":   request.Email,
 		"desc":    request.Desc,
-	})
-	chain := blockchain.CreateChain(payload)
+	}
+	if !request.Mnemonic {
+		chain := services.CreateChain(metadata)
+		return &manage.ChainCreationResponse{
+			Ref: chain.Ref,
+			Id:  chain.ID,
+			Wif: chain.WIF(),
+		}, nil
+	}
+
+	chain, mnemonic, err := services.CreateMnemonicChain(metadata, request.Passphrase)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &manage.ChainCreationResponse{
+		Ref:      chain.Ref,
+		Id:       chain.ID,
+		Wif:      chain.WIF(),
+		Mnemonic: mnemonic,
+	}, nil
+}
+
+func (*ManageServer) RecoverChain(ctx context.Context, request *manage.ChainRecoveryRequest) (*manage.ChainCreationResponse, error) {
+	chain, err := services.RecoverChain(request.Mnemonic, request.Passphrase, request.ChainID)
+	if err != nil {
+		return nil, statusError(err)
+	}
 	return &manage.ChainCreationResponse{
 		Ref: chain.Ref,
 		Id:  chain.ID,
@@ -169,66 +306,120 @@ func (*ManageServer) CreateChain(ctx context.Context, request *manage.ChainCreat
 func (*ManageServer) CreateBlock(ctx context.Context, request *manage.BlockCreationRequest) (*manage.BlockCreationResponse, error) {
 	utils.L.Debug("start creating a block")
 
-	chain := blockchain.LoadChain(request.ChainID)
-	if chain == nil {
-		return nil, nil
+	block, err := services.CreateBlock(request.ChainID, request.Payload)
+	if err != nil {
+		return nil, statusError(err)
 	}
-	if !chain.IsOwner() {
-		return nil, nil
+
+	return blockCreationResponse(block), nil
+}
+
+// Chunks of payload are joined, chain ID is taken from the first chunk
+func (*ManageServer) CreateBlockStream(stream manage.IFCManage_CreateBlockStreamServer) error {
+	utils.L.Debug("start receiving a block")
+
+	var chainID string
+	var payload []byte
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(chainID) == 0 {
+			chainID = request.ChainID
+		}
+		payload = append(payload, request.Payload...)
+		if len(payload) > services.MaxPayloadSize {
+			return statusError(&services.ManageError{
+				Kind:    services.InvalidArgument,
+				Message: fmt.Sprintf("payload is larger than %v bytes", services.MaxPayloadSize),
+			})
+		}
 	}
 
-	block := chain.CreateBlock(request.Payload)
-	if chain.SaveBlock(block) {
-		b := &protocol.BroadcastBlock{}
-		b.SetBlock(block)
-		defer func() { protocol.BroadcastChannel <- b }()
+	block, err := services.CreateBlock(chainID, payload)
+	if err != nil {
+		return statusError(err)
 	}
+	return stream.SendAndClose(blockCreationResponse(block))
+}
 
+func blockCreationResponse(block *blockchain.Block) *manage.BlockCreationResponse {
 	return &manage.BlockCreationResponse{
 		Height:    block.Height,
 		Time:      block.Time,
-
 		PrevHash:  block.PrevHash,
 		Hash:      block.Hash,
 		Signature: block.Signature,
-	}, nil
+	}
+}
+
+func (*ManageServer) GenerateBlocks(ctx context.Context, request *manage.GenerateBlocksRequest) (*manage.GenerateBlocksResponse, error) {
+	blocks, err := services.GenerateBlocks(request.ChainID, int(request.Count))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &manage.GenerateBlocksResponse{}
+	for _, block := range blocks {
+		response.Hashes = append(response.Hashes, block.Hash)
+	}
+	return response, nil
 }
 
 func (*ManageServer) AddChain(ctx context.Context, request *manage.ChainRequest) (*manage.CommonResponse, error) {
-	blockchain.NewReadonlyChain(request.Id).Sync()
+	if err := services.AddChain(request.Id); err != nil {
+		return nil, statusError(err)
+	}
 	return &manage.CommonResponse{Success: true}, nil
 }
 
 func (*ManageServer) DeleteChain(ctx context.Context, request *manage.ChainRequest) (*manage.CommonResponse, error) {
-	if chain := blockchain.LoadChain(request.Id); chain != nil {
-		blockchain.SharedStorage().CleanChain(chain)
-		blockchain.ResetChainCache()
-		return &manage.CommonResponse{Success: true}, nil
-	} else {
-		return &manage.CommonResponse{Success: false, Error: "deleting chain is not exist"}, nil
+	if err := services.DeleteChain(request.Id); err != nil {
+		return nil, statusError(err)
 	}
+	return &manage.CommonResponse{Success: true}, nil
 }
 
-func (*ManageServer) GetPeers(request *manage.PeerListRequest, stream manage.IFCManage_GetPeersServer) error {
-	peers := network.SharedStorage().GetPeers(int(request.Count))
-	for _, peer := range peers {
-		var response *manage.PeerResponse
-		online := services.SharedServer.Peers[peer.Addr]
-		if online == nil {
-			response = &manage.PeerResponse{
-				Addr:   peer.Addr,
-				Rank:   int32(peer.Rank),
-				Last:   peer.Last.Unix(),
-				Server: peer.IsServer,
-				Online: false}
-		} else {
-			response = &manage.PeerResponse{
-				Addr:   online.Addr,
-				Rank:   int32(online.Rank),
-				Last:   online.Last.Unix(),
-				Server: online.IsServer,
-				Online: true}
+func (*ManageServer) GetAvailableChains(request *manage.ChainRequest, stream manage.IFCManage_GetAvailableChainsServer) error {
+	chains, err := blockchain.GetAvailableChains()
+	if err != nil {
+		return statusError(err)
+	}
+	for _, chain := range chains {
+		if len(request.Id) > 0 && chain.ID != request.Id {
+			continue
+		}
+		metadata := chain.Metadata()
+		err := stream.Send(&manage.AvailableChainResponse{
+			Id:      chain.ID,
+			Name:    metadata["name"],
+			Author:  metadata["author"],
+			Website: metadata["website"],
+			Email:   metadata["email"],
+			Desc:    metadata["desc"],
+			Count:   chain.Count,
+			Last:    chain.Last.Unix(),
+		})
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+func (*ManageServer) GetPeers(request *manage.PeerListRequest, stream manage.IFCManage_GetPeersServer) error {
+	for _, peer := range services.GetPeers(int(request.Count)) {
+		response := &manage.PeerResponse{
+			Addr:   peer.Addr,
+			Rank:   int32(peer.Rank),
+			Last:   peer.Last.Unix(),
+			Server: peer.IsServer,
+			Online: peer.Online}
 		if err := stream.Send(response); err != nil {
 			return err
 		}
@@ -237,73 +428,185 @@ func (*ManageServer) GetPeers(request *manage.PeerListRequest, stream manage.IFC
 }
 
 func (*ManageServer) AddPeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
-	peer := &network.Peer{Addr: request.Addr, Rank: 100}
-	peer.Save()
+	if _, err := services.AddPeer(request.Addr); err != nil {
+		return nil, statusError(err)
+	}
 	return &manage.CommonResponse{Success: true}, nil
 }
 
 func (*ManageServer) ConnectPeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
-	if peer := services.SharedServer.Peers[request.Addr]; peer != nil {
-		return &manage.CommonResponse{Success: false, Error: "already connected"}, nil
+	if err := services.ConnectPeer(request.Addr); err != nil {
+		return nil, statusError(err)
+	}
+	return &manage.CommonResponse{Success: true}, nil
+}
+
+func (*ManageServer) DisconnPeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
+	if err := services.DisconnPeer(request.Addr); err != nil {
+		return nil, statusError(err)
+	}
+	return &manage.CommonResponse{Success: true}, nil
+}
+
+func (*ManageServer) DeletePeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
+	if err := services.DeletePeer(request.Addr); err != nil {
+		return nil, statusError(err)
 	}
+	return &manage.CommonResponse{Success: true}, nil
+}
 
-	if peer := network.SharedStorage().GetPeer(request.Addr); peer != nil {
-		if err := services.SharedServer.Connect(peer); err != nil {
-			return &manage.CommonResponse{Success: false, Error: err.Error()}, nil
+func (*ManageServer) GetDeadWebhooks(request *manage.WebhookListRequest, stream manage.IFCManage_GetDeadWebhooksServer) error {
+	for _, delivery := range webhook.SharedStorage().GetDeadDeliveries(int(request.Count)) {
+		err := stream.Send(&manage.WebhookDeliveryResponse{
+			Id:       delivery.ID,
+			Url:      delivery.URL,
+			ChainID:  delivery.ChainID,
+			Height:   delivery.Height,
+			Attempts: int32(delivery.Attempts),
+			Error:    delivery.Error,
+			Next:     delivery.Next.Unix(),
+		})
+		if err != nil {
+			return err
 		}
-		return &manage.CommonResponse{Success: true}, nil
 	}
+	return nil
+}
 
-	peer := network.NewPeer(request.Addr, 100)
-	if err := services.SharedServer.Connect(peer); err != nil {
-		return &manage.CommonResponse{Success: false, Error: err.Error()}, nil
+func (*ManageServer) RetryWebhook(ctx context.Context, request *manage.WebhookRequest) (*manage.CommonResponse, error) {
+	if err := services.RetryWebhook(request.Id); err != nil {
+		return nil, statusError(err)
 	}
 	return &manage.CommonResponse{Success: true}, nil
 }
 
-func (*ManageServer) DisconnPeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
-	if peer := services.SharedServer.Peers[request.Addr]; peer != nil {
-		close(peer.Send)
-		return &manage.CommonResponse{Success: true}, nil
+func (*ManageServer) DeleteWebhook(ctx context.Context, request *manage.WebhookRequest) (*manage.CommonResponse, error) {
+	if err := services.DeleteWebhook(request.Id); err != nil {
+		return nil, statusError(err)
 	}
-	return &manage.CommonResponse{Success: false, Error: "peer is not connected"}, nil
+	return &manage.CommonResponse{Success: true}, nil
 }
 
-func (*ManageServer) DeletePeer(ctx context.Context, request *manage.PeerRequest) (*manage.CommonResponse, error) {
-	if peer := services.SharedServer.Peers[request.Addr]; peer != nil {
-		close(peer.Send)
-		network.SharedStorage().DeletePeer(peer)
-		return &manage.CommonResponse{Success: true}, nil
+func GetNodeStatus() error {
+	in, err := IFCManageClient.GetNodeStatus(context.Background(), &manage.NodeStatusRequest{})
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(in)
+		return nil
 	}
-	if peer := network.SharedStorage().GetPeer(request.Addr); peer != nil {
-		network.SharedStorage().DeletePeer(peer)
-		return &manage.CommonResponse{Success: true}, nil
+
+	fmt.Printf("[Version  ] v%v (protocol v%v)\n", in.Version, in.Protocol)
+	fmt.Printf("[Network  ] %v\n", in.Network)
+	fmt.Printf("[Uptime   ] %v\n", time.Duration(in.Uptime)*time.Second)
+	fmt.Printf("[Node ID  ] %v\n", in.NodeID)
+	fmt.Printf("[Peers    ] %v inbound, %v outbound\n", in.Inbound, in.Outbound)
+	fmt.Printf("[Traffic  ] %v bytes in, %v bytes out\n", in.BytesReceived, in.BytesSent)
+	fmt.Printf("[Database ] %v bytes\n", in.DatabaseSize)
+	fmt.Printf("[Payloads ] %v bytes\n", in.PayloadSize)
+
+	fmt.Println("[Config   ]")
+	var keys []string
+	for k := range in.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("\t%v: %v\n", k, in.Config[k])
 	}
-	return &manage.CommonResponse{Success: false, Error: "peer is not exist"}, nil
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Chain ID", "Block Count", "Best Known", "Synced"})
+	for _, chain := range in.Chains {
+		table.Append([]string{
+			chain.Id,
+			strconv.FormatUint(chain.Count, 10),
+			strconv.FormatUint(chain.Best, 10),
+			fmt.Sprintf("%.1f%%", chain.Progress*100),
+		})
+	}
+	table.Render()
+
+	types := map[string]bool{}
+	for k := range in.MessagesReceived {
+		types[k] = true
+	}
+	for k := range in.MessagesSent {
+		types[k] = true
+	}
+	keys = nil
+	for k := range types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Message Type", "Received", "Sent"})
+	for _, k := range keys {
+		table.Append([]string{
+			k,
+			strconv.FormatUint(in.MessagesReceived[k], 10),
+			strconv.FormatUint(in.MessagesSent[k], 10),
+		})
+	}
+	table.Render()
+	return nil
 }
 
-func GetPeers(count int32) {
+func ReloadConfig() error {
+	response, err := IFCManageClient.ReloadConfig(context.Background(), &manage.ReloadConfigRequest{})
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(response)
+		return nil
+	}
+
+	if len(response.Applied) == 0 && len(response.Restart) == 0 {
+		fmt.Println("Nothing changed")
+		return nil
+	}
+	for _, key := range response.Applied {
+		fmt.Printf("[Applied] %v\n", key)
+	}
+	for _, key := range response.Restart {
+		fmt.Printf("[Restart] %v (takes effect after restart)\n", key)
+	}
+	return nil
+}
+
+func GetPeers(count int32) error {
 	stream, err := IFCManageClient.GetPeers(context.Background(), &manage.PeerListRequest{Count: count})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Address", "Rank", "Last/Duration", "Type", "Online?"})
-	cachedPeers = map[string]bool{}
+	var peers []*manage.PeerResponse
 	for {
 		in, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
+		peers = append(peers, in)
+	}
 
+	cachedPeers = map[string]bool{}
+	for _, in := range peers {
 		cachedPeers[in.Addr] = in.Online
+	}
+	if jsonOutput() {
+		printJSON(peers)
+		return nil
+	}
 
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Address", "Rank", "Last/Duration", "Type", "Online?"})
+	for _, in := range peers {
 		duration := "never"
 		if in.Last > 0 {
 			if in.Online {
@@ -326,56 +629,132 @@ func GetPeers(count int32) {
 		table.Append([]string{in.Addr, strconv.Itoa(int(in.Rank)), duration, t, online})
 	}
 	table.Render()
+	return nil
 }
 
-func GetChains(id string) {
-	stream, err := IFCManageClient.GetChains(context.Background(), &manage.ChainRequest{Id: id})
+func GetChains(id string) error {
+	chains, err := fetchChains(id)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	if jsonOutput() {
+		printJSON(chains)
+		return nil
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader([]string{"Ref", "Chain ID", "Block Count"})
-	cachedChains = map[int64]*cachedChain{}
+	for _, in := range chains {
+		table.Append([]string{strconv.FormatInt(in.Ref, 10), in.Id, strconv.FormatUint(in.Count, 10)})
+	}
+	table.Render()
+	return nil
+}
+
+// Chains with the ID, or all chains if ID is empty, refs are cached for 'use'
+func fetchChains(id string) ([]*manage.ChainResponse, error) {
+	stream, err := IFCManageClient.GetChains(context.Background(), &manage.ChainRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	var chains []*manage.ChainResponse
 	for {
 		in, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			fmt.Println()
-			return
+			return nil, err
 		}
+		chains = append(chains, in)
+	}
+
+	if len(id) == 0 {
+		cachedChains = map[int64]*cachedChain{}
+	}
+	for _, in := range chains {
 		cachedChains[in.Ref] = &cachedChain{
 			in.Id,
 			in.Ref,
 			in.Count,
 		}
-		table.Append([]string{strconv.FormatInt(in.Ref, 10), in.Id, strconv.FormatUint(in.Count, 10)})
+	}
+	return chains, nil
+}
+
+func GetAvailableChains() error {
+	stream, err := IFCManageClient.GetAvailableChains(context.Background(), &manage.ChainRequest{})
+	if err != nil {
+		return err
+	}
+
+	var chains []*manage.AvailableChainResponse
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chains = append(chains, in)
+	}
+	if jsonOutput() {
+		printJSON(chains)
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Chain ID", "Name", "Author", "Website", "Block Count", "Last Seen"})
+	for _, in := range chains {
+		table.Append([]string{
+			in.Id,
+			in.Name,
+			in.Author,
+			in.Website,
+			strconv.FormatUint(in.Count, 10),
+			time.Unix(in.Last, 0).String(),
+		})
 	}
 	table.Render()
+	fmt.Println("run 'addchain [chain id]' to subscribe a chain")
+	return nil
 }
 
-func GetBlocks(id string, from uint64, to uint64) {
+func fetchBlocks(id string, from uint64, to uint64) ([]*manage.BlockResponse, error) {
 	stream, err := IFCManageClient.GetBlocks(context.Background(), &manage.BlockRequest{ChainID: id, From: from, To: to})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Height", "Time", "Prev Hash", "Hash", "Signature"})
+	var blocks []*manage.BlockResponse
 	for {
 		in, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			fmt.Println(err)
-			return
+			return nil, err
 		}
+		blocks = append(blocks, in)
+	}
+	return blocks, nil
+}
+
+func GetBlocks(id string, from uint64, to uint64) error {
+	blocks, err := fetchBlocks(id, from, to)
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(blocks)
+		return nil
+	}
 
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Height", "Time", "Prev Hash", "Hash", "Signature"})
+	for _, in := range blocks {
 		prev := in.PrevHash
 		if len(prev) > 0 {
 			prev = prev[:6]
@@ -390,61 +769,138 @@ func GetBlocks(id string, from uint64, to uint64) {
 	}
 
 	table.Render()
+	return nil
+}
+
+func DumpBlock(id string, height uint64) error {
+	blocks, err := fetchBlocks(id, height, height)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("block %v not found", height)
+	}
+	if jsonOutput() {
+		printJSON(blocks[0])
+		return nil
+	}
+
+	printBlock(blocks[0])
+	return nil
 }
 
-func DumpBlock(id string, height uint64) {
-	stream, err := IFCManageClient.GetBlocks(context.Background(), &manage.BlockRequest{ChainID: id, From: height, To: height})
+// Look up a block by hash in the chain, or in all chains if chain ID is empty,
+// online peers are asked too if remote is set
+func FindBlock(id string, hash string, remote bool) error {
+	in, err := IFCManageClient.GetBlockByHash(context.Background(), &manage.BlockHashRequest{ChainID: id, Hash: hash, Remote: remote})
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(in)
+		return nil
+	}
+
+	fmt.Printf("[Chain ID ] %v\n", in.ChainID)
+	printBlock(in)
+	return nil
+}
+
+// Follow new blocks of the chain like 'tail -f', last blocks are printed first,
+// stopped by Ctrl-C. Blocks are printed as JSON lines with json output.
+func TailBlocks(id string, last uint64) error {
+	chains, err := IFCManageClient.GetChains(context.Background(), &manage.ChainRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	chain, err := chains.Recv()
+	if err != nil {
+		return err
+	}
+
+	from := uint64(0)
+	if chain.Count > last {
+		from = chain.Count - last
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stream, err := IFCManageClient.WatchBlocks(ctx, &manage.WatchRequest{ChainID: id, Replay: true, From: from})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	for {
 		in, err := stream.Recv()
-		if err == io.EOF {
-			break
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
 		}
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
+		}
+		if jsonOutput() {
+			printJSONLine(in)
+			continue
 		}
 
-		fmt.Printf("[Height   ] %v\n", in.Height)
-		fmt.Printf("[Time     ] %v\n", in.Time)
-		fmt.Printf("[PrevHash ] %v\n", in.PrevHash)
-		fmt.Printf("[Hash     ] %v\n", in.Hash)
-		fmt.Printf("[Signature] %v\n", in.Signature)
+		payload := string(in.Payload)
+		if len(payload) > 50 {
+			payload = payload[:50] + " ..."
+		}
+		fmt.Printf("[%v] %v %v %v\n", in.Height, time.Unix(int64(in.Time), 0).Format(time.RFC3339), in.Hash, payload)
+	}
+}
 
-		buffer := &bytes.Buffer{}
-		if err := json.Indent(buffer, in.Payload, "", "\t"); err != nil {
-			if len(in.Payload) > 50 {
-				fmt.Printf("[Payload  ] (%v bytes) %v ...\n", len(in.Payload), string(in.Payload[:50]))
-			} else {
-				fmt.Printf("[Payload  ] %v\n", string(in.Payload))
-			}
+func printBlock(in *manage.BlockResponse) {
+	fmt.Printf("[Height   ] %v\n", in.Height)
+	fmt.Printf("[Time     ] %v\n", in.Time)
+	fmt.Printf("[PrevHash ] %v\n", in.PrevHash)
+	fmt.Printf("[Hash     ] %v\n", in.Hash)
+	fmt.Printf("[Signature] %v\n", in.Signature)
+
+	buffer := &bytes.Buffer{}
+	if err := json.Indent(buffer, in.Payload, "", "\t"); err != nil {
+		if len(in.Payload) > 50 {
+			fmt.Printf("[Payload  ] (%v bytes) %v ...\n", len(in.Payload), string(in.Payload[:50]))
 		} else {
-			fmt.Println("[Payload  ]")
-			_, err := buffer.WriteTo(os.Stdout)
-			if err != nil {
-				log.Fatal(err)
-			}
-			fmt.Println()
+			fmt.Printf("[Payload  ] %v\n", string(in.Payload))
 		}
+	} else {
+		fmt.Println("[Payload  ]")
+		_, err := buffer.WriteTo(os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println()
 	}
 }
 
-func CreateChain(name string, author string, website string, email string, desc string) {
+// Key of the chain is derived from a new mnemonic if mnemonic is set
+func CreateChain(name string, author string, website string, email string, desc string, mnemonic bool, passphrase string) error {
 	response, err := IFCManageClient.CreateChain(context.Background(), &manage.ChainCreationRequest{
-		Name:    name,
-		Author:  author,
-		Website: website,
-		Email:   email,
-		Desc:    desc,
+		Name:       name,
+		Author:     author,
+		Website:    website,
+		Email:      email,
+		Desc:       desc,
+		Mnemonic:   mnemonic,
+		Passphrase: passphrase,
 	})
 
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	cachedChains[response.Ref] = &cachedChain{
@@ -452,17 +908,71 @@ func CreateChain(name string, author string, website string, email string, desc
 		response.Ref,
 		1,
 	}
+	if jsonOutput() {
+		printJSON(response)
+		return nil
+	}
 
 	fmt.Printf("[Ref     ] %v\n", response.Ref)
 	fmt.Printf("[Chain ID] %v\n", response.Id)
 	fmt.Printf("[WIF     ] %v\n", response.Wif)
+	if len(response.Mnemonic) > 0 {
+		fmt.Printf("[Mnemonic] %v\n", response.Mnemonic)
+		fmt.Println("Write down the words and keep them with the passphrase, they are not shown again")
+	}
+	return nil
 }
 
-func CreateBlock(id string, payload []byte) {
-	response, err := IFCManageClient.CreateBlock(context.Background(), &manage.BlockCreationRequest{ChainID: id, Payload: payload})
+// Chain is owned again, blocks are synced from peers later
+func RecoverChain(mnemonic string, passphrase string, id string) error {
+	response, err := IFCManageClient.RecoverChain(context.Background(), &manage.ChainRecoveryRequest{
+		Mnemonic:   mnemonic,
+		Passphrase: passphrase,
+		ChainID:    id,
+	})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+
+	cachedChains[response.Ref] = &cachedChain{
+		response.Id,
+		response.Ref,
+		0,
+	}
+	if jsonOutput() {
+		printJSON(response)
+		return nil
+	}
+
+	fmt.Printf("[Ref     ] %v\n", response.Ref)
+	fmt.Printf("[Chain ID] %v\n", response.Id)
+	fmt.Println("Recovered, blocks will be synced from peers")
+	return nil
+}
+
+// Messages carry a block of the largest payload besides other fields,
+// both the service and cli raise the 4MB default limit of gRPC to it
+const maxMessageSize = services.MaxPayloadSize + 1024*1024
+
+// Services of earlier versions reject messages larger than 4MB,
+// larger payloads are sent by CreateBlockStream in chunks
+const maxMessagePayload = 4*1024*1024 - 1024
+const payloadChunkSize = 1024 * 1024
+
+func CreateBlock(id string, payload []byte) error {
+	var response *manage.BlockCreationResponse
+	var err error
+	if len(payload) > maxMessagePayload {
+		response, err = streamBlock(id, payload)
+	} else {
+		response, err = IFCManageClient.CreateBlock(context.Background(), &manage.BlockCreationRequest{ChainID: id, Payload: payload})
+	}
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(response)
+		return nil
 	}
 
 	fmt.Printf("[Height   ] %v\n", response.Height)
@@ -470,112 +980,200 @@ func CreateBlock(id string, payload []byte) {
 	fmt.Printf("[PrevHash ] %v\n", response.PrevHash)
 	fmt.Printf("[Hash     ] %v\n", response.Hash)
 	fmt.Printf("[Signature] %v\n", response.Signature)
+	return nil
 }
 
-func AddChain(id string) {
-	response, err := IFCManageClient.AddChain(context.Background(), &manage.ChainRequest{Id: id})
+func streamBlock(id string, payload []byte) (*manage.BlockCreationResponse, error) {
+	stream, err := IFCManageClient.CreateBlockStream(context.Background())
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
-	if response.Success {
-		fmt.Println("Added")
-		GetChains(id)
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	for i := 0; i < len(payload); i += payloadChunkSize {
+		end := i + payloadChunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		err := stream.Send(&manage.BlockCreationRequest{ChainID: id, Payload: payload[i:end]})
+		if err == io.EOF {
+			// stream is closed by the service, error is returned by CloseAndRecv
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
+	return stream.CloseAndRecv()
 }
 
-func DeleteChain(ref int64) {
-	chain, ok := cachedChains[ref]
-	if !ok {
-		fmt.Println("unknown ref of chain, run 'chains' first")
-		return
+func GenerateBlocks(id string, count int) error {
+	response, err := IFCManageClient.GenerateBlocks(context.Background(), &manage.GenerateBlocksRequest{ChainID: id, Count: int32(count)})
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		printJSON(response)
+		return nil
 	}
 
-	fmt.Printf("Are you sure to DELETE chain %v with all %v blocks? (y/N) ", chain.ID, chain.Count)
-	var confirm string
-	if _, err := fmt.Scanln(&confirm); err != nil {
-		return
+	for _, hash := range response.Hashes {
+		fmt.Println(hash)
 	}
+	fmt.Printf("%v blocks generated\n", len(response.Hashes))
+	return nil
+}
 
-	if confirm != "y" && confirm != "Y" {
-		return
+func AddChain(id string) error {
+	_, err := IFCManageClient.AddChain(context.Background(), &manage.ChainRequest{Id: id})
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("Deleting...")
+	if !jsonOutput() {
+		fmt.Println("Added")
+	}
+	return GetChains(id)
+}
 
-	response, err := IFCManageClient.DeleteChain(context.Background(), &manage.ChainRequest{Id: chain.ID})
+// Confirmed from stdin unless yes is set, which is required with json output
+func DeleteChain(id string, yes bool) error {
+	chains, err := fetchChains(id)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	if len(chains) == 0 {
+		return fmt.Errorf("chain %v not found", id)
 	}
+	chain := chains[0]
 
-	if response.Success {
-		fmt.Println("Deleted")
-		delete(cachedChains, ref)
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	if !yes {
+		if jsonOutput() {
+			return errors.New("use --yes to delete a chain with json output")
+		}
+		fmt.Printf("Are you sure to DELETE chain %v with all %v blocks? (y/N) ", chain.Id, chain.Count)
+		var confirm string
+		if _, err := fmt.Scanln(&confirm); err != nil {
+			return nil
+		}
+
+		if confirm != "y" && confirm != "Y" {
+			return nil
+		}
+
+		fmt.Println("Deleting...")
+	}
+
+	_, err = IFCManageClient.DeleteChain(context.Background(), &manage.ChainRequest{Id: chain.Id})
+	if err != nil {
+		return err
 	}
+
+	printDone("Deleted")
+	delete(cachedChains, chain.Ref)
+	if chainContext != nil && chainContext.ID == chain.Id {
+		chainContext = nil
+	}
+	return nil
 }
 
-func AddPeer(addr string) {
-	response, err := IFCManageClient.AddPeer(context.Background(), &manage.PeerRequest{Addr: addr})
+func AddPeer(addr string) error {
+	_, err := IFCManageClient.AddPeer(context.Background(), &manage.PeerRequest{Addr: addr})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	if response.Success {
-		fmt.Println("Added")
-		cachedPeers[addr] = false
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	printDone("Added")
+	cachedPeers[addr] = false
+	return nil
+}
+
+func ConnectPeer(addr string) error {
+	_, err := IFCManageClient.ConnectPeer(context.Background(), &manage.PeerRequest{Addr: addr})
+	if err != nil {
+		return err
 	}
+
+	printDone("Connected")
+	cachedPeers[addr] = true
+	return nil
 }
 
-func ConnectPeer(addr string) {
-	response, err := IFCManageClient.ConnectPeer(context.Background(), &manage.PeerRequest{Addr: addr})
+func DisconnPeer(addr string) error {
+	_, err := IFCManageClient.DisconnPeer(context.Background(), &manage.PeerRequest{Addr: addr})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	if response.Success {
-		fmt.Println("Connected")
-		cachedPeers[addr] = true
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	printDone("Disconnected")
+	cachedPeers[addr] = false
+	return nil
+}
+
+func DeletePeer(addr string) error {
+	_, err := IFCManageClient.DeletePeer(context.Background(), &manage.PeerRequest{Addr: addr})
+	if err != nil {
+		return err
 	}
+
+	printDone("Deleted")
+	delete(cachedPeers, addr)
+	return nil
 }
 
-func DisconnPeer(addr string) {
-	response, err := IFCManageClient.DisconnPeer(context.Background(), &manage.PeerRequest{Addr: addr})
+func GetDeadWebhooks(count int32) error {
+	stream, err := IFCManageClient.GetDeadWebhooks(context.Background(), &manage.WebhookListRequest{Count: count})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	if response.Success {
-		fmt.Println("Disconnected")
-		cachedPeers[addr] = false
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	var deliveries []*manage.WebhookDeliveryResponse
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, in)
+	}
+	if jsonOutput() {
+		printJSON(deliveries)
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "URL", "Chain ID", "Height", "Attempts", "Error"})
+	for _, in := range deliveries {
+		table.Append([]string{
+			strconv.FormatInt(in.Id, 10),
+			in.Url,
+			in.ChainID,
+			strconv.FormatUint(in.Height, 10),
+			strconv.Itoa(int(in.Attempts)),
+			in.Error,
+		})
 	}
+	table.Render()
+	return nil
 }
 
-func DeletePeer(addr string) {
-	response, err := IFCManageClient.DeletePeer(context.Background(), &manage.PeerRequest{Addr: addr})
+func RetryWebhook(id int64) error {
+	_, err := IFCManageClient.RetryWebhook(context.Background(), &manage.WebhookRequest{Id: id})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	if response.Success {
-		fmt.Println("Deleted")
-		delete(cachedPeers, addr)
-	} else {
-		fmt.Printf("%v\n", response.Error)
+	printDone("Queued")
+	return nil
+}
+
+func DeleteWebhook(id int64) error {
+	_, err := IFCManageClient.DeleteWebhook(context.Background(), &manage.WebhookRequest{Id: id})
+	if err != nil {
+		return err
 	}
-}
\ No newline at end of file
+
+	printDone("Deleted")
+	return nil
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Set by '--output', table for people and json for scripts
+var outputFlag string
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func jsonOutput() bool {
+	return outputFlag == outputJSON
+}
+
+func checkOutput() error {
+	if outputFlag != outputTable && outputFlag != outputJSON {
+		return fmt.Errorf("output '%v' is not supported, use table or json", outputFlag)
+	}
+	return nil
+}
+
+// Print messages of manage service as indented JSON, keys are
+// snake case like the HTTP API and zero values are kept
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(jsonValue(reflect.ValueOf(v)), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// One compact JSON object per line, used for following streams
+func printJSONLine(v interface{}) {
+	data, err := json.Marshal(jsonValue(reflect.ValueOf(v)))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// Messages are converted to maps since generated fields are tagged
+// with omitempty, which drops false, 0 and empty lists
+func jsonValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonValue(v.Elem())
+	case reflect.Struct:
+		object := map[string]interface{}{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if len(field.PkgPath) > 0 || strings.HasPrefix(field.Name, "XXX_") {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if len(name) == 0 {
+				name = field.Name
+			}
+			object[snakeCase(name)] = jsonValue(v.Field(i))
+		}
+		return object
+	case reflect.Slice:
+		// bytes are encoded as base64
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = jsonValue(v.Index(i))
+		}
+		return list
+	case reflect.Map:
+		object := map[string]interface{}{}
+		for _, key := range v.MapKeys() {
+			object[fmt.Sprintf("%v", key.Interface())] = jsonValue(v.MapIndex(key))
+		}
+		return object
+	default:
+		return v.Interface()
+	}
+}
+
+// chainID -> chain_id, bytesReceived -> bytes_received
+func snakeCase(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && unicode.IsLower(runes[i-1]) {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// Result of a command without output, such as "Added"
+func printDone(result string) {
+	if jsonOutput() {
+		printJSON(map[string]string{"result": strings.ToLower(result)})
+		return
+	}
+	fmt.Println(result)
+}
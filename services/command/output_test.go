@@ -0,0 +1,77 @@
+package command
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"chainID":       "chain_id",
+		"NodeID":        "node_id",
+		"ID":            "id",
+		"bytesReceived": "bytes_received",
+		"Count":         "count",
+		"height":        "height",
+	}
+	for name, expected := range cases {
+		if result := snakeCase(name); result != expected {
+			t.Errorf("%v: unexpected %v, expected %v", name, result, expected)
+		}
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	type inner struct {
+		Height uint64 `json:"height,omitempty"`
+	}
+	type message struct {
+		ChainID              string            `protobuf:"bytes,1" json:"chainID,omitempty"`
+		NodeID               string            `json:"NodeID,omitempty"`
+		Owner                bool              `json:"owner,omitempty"`
+		Count                uint64            `json:"count,omitempty"`
+		Payload              []byte            `json:"payload,omitempty"`
+		Peers                []string          `json:"peers,omitempty"`
+		Block                *inner            `json:"block,omitempty"`
+		Blocks               []*inner          `json:"blocks,omitempty"`
+		Sent                 map[string]uint64 `json:"sent,omitempty"`
+		BytesReceived        uint64
+		XXX_NoUnkeyedLiteral struct{}
+		state                int
+	}
+
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"zero values", &message{},
+			`{"block":null,"blocks":[],"bytes_received":0,"chain_id":"","count":0,"node_id":"","owner":false,"payload":null,"peers":[],"sent":{}}`},
+		{"values", &message{
+			ChainID:       "chain",
+			NodeID:        "node",
+			Owner:         true,
+			Count:         2,
+			Payload:       []byte("hi"),
+			Peers:         []string{"ws://a"},
+			Block:         &inner{},
+			Blocks:        []*inner{{Height: 1}},
+			Sent:          map[string]uint64{"info": 3},
+			BytesReceived: 4,
+			state:         5,
+		}, `{"block":{"height":0},"blocks":[{"height":1}],"bytes_received":4,"chain_id":"chain","count":2,"node_id":"node","owner":true,"payload":"aGk=","peers":["ws://a"],"sent":{"info":3}}`},
+		{"nil", nil, `null`},
+		{"list", []*inner{nil, {}}, `[null,{"height":0}]`},
+	}
+
+	for _, c := range cases {
+		data, err := json.Marshal(jsonValue(reflect.ValueOf(c.value)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.expected {
+			t.Errorf("%v: unexpected %s", c.name, data)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"errors"
 	"github.com/spf13/cobra"
 	"math/rand"
 	"strconv"
@@ -8,10 +9,11 @@ import (
 
 var createBlock = &cobra.Command{
 	Use: "t_cb",
-	Run: func(cmd *cobra.Command, args []string) {
-		if chainContext == nil {
-			print("chain context not set\n")
-			return
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := chainID(cmd)
+		if err != nil {
+			return err
 		}
 
 		b, _ := strconv.Atoi(cmd.Flag("bytes").Value.String())
@@ -24,8 +26,7 @@ var createBlock = &cobra.Command{
 		_ = cmd.Flag("megabytes").Value.Set("0")
 
 		if size == 0 {
-			print("size should be greater than 0\n")
-			return
+			return errors.New("size should be greater than 0")
 		}
 
 		content := make([]byte, size)
@@ -33,7 +34,7 @@ var createBlock = &cobra.Command{
 			content[i] = byte(rand.Intn(90) + 32)
 		}
 
-		CreateBlock(chainContext.ID, content)
+		return CreateBlock(id, content)
 	},
 }
 
@@ -41,6 +42,5 @@ func initPerformanceCommands() {
 	createBlock.Flags().IntP("bytes", "b", 0, "bytes")
 	createBlock.Flags().IntP("kilobytes", "k", 0, "kilobytes")
 	createBlock.Flags().IntP("megabytes", "m", 0, "megabytes")
-
-	cliRootCmd.AddCommand(createBlock)
+	createBlock.Flags().String("chain", "", "chain ID")
 }
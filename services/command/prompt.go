@@ -3,9 +3,11 @@ package command
 import (
 	"fmt"
 	"github.com/Infnote/infnotechain/services/codegen"
+	"github.com/Infnote/infnotechain/utils"
 	"github.com/c-bata/go-prompt"
 	"google.golang.org/grpc"
 	"log"
+	"net"
 	"os"
 	"sort"
 	"strings"
@@ -72,25 +74,35 @@ func Completer(doc prompt.Document) []prompt.Suggest {
 
 	if len(args) == 1 {
 		s := []prompt.Suggest{
+			{Text: "status", Description: "Print status of the service"},
+			{Text: "reload", Description: "Reload config file of the service"},
 			{Text: "peers", Description: "Print online peers"},
 			{Text: "chains", Description: "Print accepted chains"},
+			{Text: "available", Description: "Print chains discovered from peers"},
 			{Text: "blocks", Description: "Print blocks"},
 			{Text: "dump", Description: "Print a block with detail"},
+			{Text: "find", Description: "Find a block by hash"},
+			{Text: "tail", Description: "Follow new blocks of current chain"},
 			{Text: "use", Description: "Set chain as current context"},
 			{Text: "createblock", Description: "Create a new block"},
+			{Text: "generate", Description: "Generate blocks on regtest"},
 			{Text: "createchain", Description: "Create a new chain"},
+			{Text: "recoverchain", Description: "Recover an owned chain from mnemonic"},
 			{Text: "addchain", Description: "Add an exist chain"},
-			{Text: "delchain", Description: "Add an exist chain"},
+			{Text: "delchain", Description: "Delete a chain"},
 			{Text: "addpeer", Description: "Add a peer"},
 			{Text: "delpeer", Description: "Delete a peer"},
 			{Text: "connect", Description: "Connect to a peer without saving"},
 			{Text: "disconnect", Description: "Disconnect to a peer"},
+			{Text: "deadhooks", Description: "Print failed webhook deliveries"},
+			{Text: "retryhook", Description: "Retry a failed webhook delivery"},
+			{Text: "delhook", Description: "Delete a failed webhook delivery"},
 			{Text: "exit", Description: "Quit the program"},
 		}
 		return prompt.FilterContains(s, doc.GetWordBeforeCursor(), true)
 	}
 
-	if args[0] == "use" && len(args) == 2 {
+	if (args[0] == "use" || args[0] == "delchain") && len(args) == 2 {
 		return cacheChainSuggest()
 	}
 
@@ -98,6 +110,8 @@ func Completer(doc prompt.Document) []prompt.Suggest {
 		return []prompt.Suggest{
 			{Text: "string"},
 			{Text: "base64"},
+			{Text: "--file"},
+			{Text: "--json"},
 		}
 	}
 
@@ -108,6 +122,8 @@ func Completer(doc prompt.Document) []prompt.Suggest {
 			{Text: "--website"},
 			{Text: "--email"},
 			{Text: "--desc"},
+			{Text: "--mnemonic"},
+			{Text: "--passphrase"},
 		}
 	}
 
@@ -126,18 +142,41 @@ func Completer(doc prompt.Document) []prompt.Suggest {
 	return nil
 }
 
-func connect() {
-	conn, err := grpc.Dial("localhost:32700", grpc.WithInsecure())
+// Connect to the service once, commands in cli share the connection
+func connect() error {
+	if IFCManageClient != nil {
+		return nil
+	}
+
+	options, err := dialOptions()
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	conn, err := grpc.Dial(manageAddress(), options...)
+	if err != nil {
+		return err
 	}
 
 	IFCManageClient = manage.NewIFCManageClient(conn)
+	return nil
+}
+
+// Address of the service from 'manage.host' and 'manage.port',
+// the service listening on all interfaces is connected by loopback
+func manageAddress() string {
+	host := utils.GetString("manage.host")
+	if ip := net.ParseIP(host); len(host) == 0 || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, utils.GetString("manage.port"))
 }
 
 func UI() {
-	initCLICommands()
-	connect()
+	if err := connect(); err != nil {
+		log.Fatal(err)
+	}
+	interactive = true
 	fmt.Println("IFC service connected")
 	ui := prompt.New(
 		Executer,
@@ -2,7 +2,9 @@ package command
 
 import (
 	"fmt"
+	"github.com/Infnote/infnotechain/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"os"
 )
 
@@ -12,6 +14,23 @@ var directCmd = &cobra.Command{
 	Long: `Infnote is a decentralized information sharing system based on blockchain and peer-to-peer network, 
 				aiming to provide an easy-to-use medium for users to share their thoughts, 
 				insights and views freely without worrying about anonymity, data tampering and data loss.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		// commands in cli are executed with the same root
+		if !configLoaded {
+			// stdout is kept for output of commands
+			if cmd != runCmd {
+				utils.SetLoggingMode(utils.STDERR)
+			}
+			utils.LoadConfig(configFlag, dataDirFlag, networkFlag)
+			configLoaded = true
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := cmd.Help(); err != nil {
 			fmt.Println(err)
@@ -19,19 +38,41 @@ var directCmd = &cobra.Command{
 	},
 }
 
-var cliRootCmd = &cobra.Command{}
+var configFlag string
+var dataDirFlag string
+var networkFlag string
+var configLoaded bool
+
+// Set when commands are executed in cli, where 'use' is available
+var interactive bool
 
 func DirectExecute() {
 	initDirectCommands()
+	initCLICommands()
+	initKeyCommands()
 	if err := directCmd.Execute(); err != nil {
-		fmt.Println(err)
+		printError(err)
 		os.Exit(1)
 	}
 }
 
+// Only commands of the service are available in cli, flags are
+// reset after execution since the commands are reused
 func CLIExecute(args []string) {
-	cliRootCmd.SetArgs(args)
-	if err := cliRootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	cmd, _, err := directCmd.Find(args)
+	if err != nil || !isClientCommand(cmd) {
+		fmt.Printf("unknown command \"%v\"\n", args[0])
+		return
+	}
+
+	output := outputFlag
+	directCmd.SetArgs(args)
+	if err := directCmd.Execute(); err != nil {
+		printError(err)
 	}
+	outputFlag = output
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
 }
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"github.com/Infnote/infnotechain/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "manage.infnote.com"
+
+var errorCodes = map[services.ErrorKind]codes.Code{
+	services.InvalidArgument:  codes.InvalidArgument,
+	services.NotFound:         codes.NotFound,
+	services.AlreadyExists:    codes.AlreadyExists,
+	services.PermissionDenied: codes.PermissionDenied,
+	services.Unavailable:      codes.Unavailable,
+	services.Unauthenticated:  codes.Unauthenticated,
+	services.Internal:         codes.Internal,
+}
+
+// Errors of manage service are gRPC status with an ErrorInfo detail,
+// reason of the detail is the kind of the error
+func statusError(err error) error {
+	e, ok := err.(*services.ManageError)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code, ok := errorCodes[e.Kind]
+	if !ok {
+		code = codes.Unknown
+	}
+	st, detailErr := status.New(code, e.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: e.Kind.String(),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, e.Message)
+	}
+	return st.Err()
+}
+
+// Print error of a call as "[kind] message", or as the errors of
+// HTTP API with json output
+func printError(err error) {
+	kind, message := errorKind(err)
+	if jsonOutput() {
+		if len(kind) == 0 {
+			kind = "Unknown"
+		}
+		printJSON(map[string]interface{}{"error": map[string]string{"code": kind, "message": message}})
+		return
+	}
+	if len(kind) == 0 {
+		fmt.Println(message)
+		return
+	}
+	fmt.Printf("[%v] %v\n", kind, message)
+}
+
+// Kind and message of an error of the service, kind is empty for other errors
+func errorKind(err error) (string, string) {
+	if e, ok := err.(*services.ManageError); ok {
+		return e.Kind.String(), e.Message
+	}
+	if st, ok := status.FromError(err); ok {
+		return errorReason(st), st.Message()
+	}
+	return "", err.Error()
+}
+
+// Kind of the error from ErrorInfo, or the gRPC code if there is no detail
+func errorReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == errorDomain {
+			return info.Reason
+		}
+	}
+	return st.Code().String()
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"errors"
+	"github.com/Infnote/infnotechain/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestErrorKind(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		kind    string
+		message string
+	}{
+		{"manage error", &services.ManageError{Kind: services.NotFound, Message: "m"}, "NotFound", "m"},
+		{"status with detail", statusError(&services.ManageError{Kind: services.Unavailable, Message: "m"}), "Unavailable", "m"},
+		{"internal", statusError(&services.ManageError{Kind: services.Internal, Message: "m"}), "Internal", "m"},
+		{"unknown error of service", statusError(errors.New("m")), "Internal", "m"},
+		{"status without detail", status.Error(codes.DeadlineExceeded, "m"), "DeadlineExceeded", "m"},
+		{"local error", errors.New("m"), "", "m"},
+	}
+
+	for _, c := range cases {
+		if kind, message := errorKind(c.err); kind != c.kind || message != c.message {
+			t.Errorf("%v: unexpected kind %q and message %q", c.name, kind, message)
+		}
+	}
+
+	st, _ := status.FromError(statusError(&services.ManageError{Kind: services.Internal, Message: "m"}))
+	if st.Code() != codes.Internal {
+		t.Errorf("unexpected code of internal errors: %v", st.Code())
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/utils"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Services started by 'run' stop when the context is canceled,
+// Wait returns after all of them stopped
+var running sync.WaitGroup
+
+func Start(ctx context.Context, service func(ctx context.Context)) {
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		service(ctx)
+	}()
+}
+
+// Services creating blocks, such as manage and API services,
+// loaded chains are flushed after all of them stopped
+var writers sync.WaitGroup
+
+func StartWriter(ctx context.Context, service func(ctx context.Context)) {
+	writers.Add(1)
+	Start(ctx, func(ctx context.Context) {
+		defer writers.Done()
+		service(ctx)
+	})
+}
+
+// Wait for services stopped, returns false if timeout
+func Wait(timeout time.Duration) bool {
+	return wait(&running, timeout)
+}
+
+// Wait for services creating blocks stopped, returns false if timeout
+func WaitWriters(timeout time.Duration) bool {
+	return wait(&writers, timeout)
+}
+
+func wait(group *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func ShutdownTimeout() time.Duration {
+	return time.Duration(utils.GetInt("daemon.timeout")) * time.Second
+}
+
+// Serve until the context is done, long-lived streams are closed
+// if they are not finished in time. It returns after handlers finished.
+func serveHTTP(ctx context.Context, server *http.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), ShutdownTimeout())
+		defer cancel()
+		if err := server.Shutdown(shutdown); err != nil {
+			_ = server.Close()
+		}
+		close(stopped)
+	}()
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	<-stopped
+	return nil
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/protocol"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/Infnote/infnotechain/webhook"
+	"net/url"
+	"time"
+)
+
+// Operations shared by the manage RPC and the HTTP API,
+// errors are returned as *ManageError with a kind for mapping to status codes
+type ErrorKind int
+
+const (
+	InvalidArgument ErrorKind = iota
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	Unavailable
+	Unauthenticated
+	Internal
+)
+
+type ManageError struct {
+	Kind    ErrorKind
+	Message string
+}
+
+type PeerStatus struct {
+	*network.Peer
+	Online bool
+}
+
+func (e ManageError) Error() string {
+	return e.Message
+}
+
+func (k ErrorKind) String() string {
+	switch k {
+	case InvalidArgument:
+		return "InvalidArgument"
+	case NotFound:
+		return "NotFound"
+	case AlreadyExists:
+		return "AlreadyExists"
+	case PermissionDenied:
+		return "PermissionDenied"
+	case Unavailable:
+		return "Unavailable"
+	case Unauthenticated:
+		return "Unauthenticated"
+	case Internal:
+		return "Internal"
+	}
+	return "Unknown"
+}
+
+func manageError(kind ErrorKind, format string, args ...interface{}) *ManageError {
+	return &ManageError{kind, fmt.Sprintf(format, args...)}
+}
+
+func GetChain(id string) (*blockchain.Chain, error) {
+	chain := blockchain.LoadChain(id)
+	if chain == nil {
+		return nil, manageError(NotFound, "chain %v is not exist", id)
+	}
+	return chain, nil
+}
+
+// Pruned payloads of light node are fetched until the context is done,
+// blocks are returned until the first one failed to fetch
+func GetBlocks(ctx context.Context, chainID string, from uint64, to uint64) ([]*blockchain.Block, error) {
+	chain, err := GetChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, manageError(InvalidArgument, "'from' should not bigger than 'to'")
+	}
+	blocks := chain.GetBlocksContext(ctx, from, to)
+	for i, block := range blocks {
+		if block.Pruned {
+			if i == 0 {
+				return nil, payloadUnavailable(block)
+			}
+			return blocks[:i], nil
+		}
+	}
+	return blocks, nil
+}
+
+func GetBlock(ctx context.Context, chainID string, height uint64) (*blockchain.Block, error) {
+	chain, err := GetChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	block := chain.GetBlockContext(ctx, height)
+	if block == nil {
+		return nil, manageError(NotFound, "block at height %v is not exist", height)
+	}
+	if block.Pruned {
+		return nil, payloadUnavailable(block)
+	}
+	return block, nil
+}
+
+// Pruned block of light node which no peer responded its payload
+func payloadUnavailable(block *blockchain.Block) error {
+	return manageError(Unavailable, "payload of block %v is unavailable, it is pruned and not fetched from peers", block.Height)
+}
+
+// Remote lookup of GetBlockByHash is bounded by this timeout
+// besides the context of the request
+const RemoteLookupTimeout = 5 * time.Second
+
+// Chain ID is optional, blocks not found locally are requested from
+// online peers only if remote is set, until the context is done
+func GetBlockByHash(ctx context.Context, chainID string, hash string, remote bool) (*blockchain.Block, error) {
+	if len(hash) == 0 {
+		return nil, manageError(InvalidArgument, "'hash' is required")
+	}
+
+	if len(chainID) > 0 {
+		chain, err := GetChain(chainID)
+		if err != nil {
+			return nil, err
+		}
+		if block := blockchain.SharedStorage().GetBlockByHash(chain.Ref, hash); block != nil {
+			if block.Pruned {
+				block = chain.GetBlockContext(ctx, block.Height)
+			}
+			if block != nil && block.Pruned {
+				return nil, payloadUnavailable(block)
+			}
+			if block != nil {
+				return block, nil
+			}
+		}
+	} else if _, block := blockchain.FindBlock(ctx, hash); block != nil {
+		if block.Pruned {
+			return nil, payloadUnavailable(block)
+		}
+		return block, nil
+	}
+
+	if remote && SharedServer != nil {
+		ctx, cancel := context.WithTimeout(ctx, RemoteLookupTimeout)
+		defer cancel()
+		block := protocol.FetchBlockByHash(ctx, hash, SharedServer.Peers())
+		if block != nil && (len(chainID) == 0 || block.ChainID() == chainID) {
+			return block, nil
+		}
+	}
+	return nil, manageError(NotFound, "block %v is not exist", hash)
+}
+
+// Genesis block payload is the metadata of the chain
+func CreateChain(metadata map[string]string) *blockchain.Chain {
+	return createChain(crypto.NewKey(), metadata)
+}
+
+// Key of the chain is derived from a new mnemonic, which is returned
+// once and should be kept with the passphrase for recovering
+func CreateMnemonicChain(metadata map[string]string, passphrase string) (*blockchain.Chain, string, error) {
+	mnemonic, err := crypto.NewMnemonic()
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := crypto.FromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+	return createChain(key, metadata), mnemonic, nil
+}
+
+func createChain(key *crypto.Key, metadata map[string]string) *blockchain.Chain {
+	payload, _ := json.Marshal(metadata)
+	chain := blockchain.CreateChainWithKey(key, payload)
+	if genesis := chain.GetBlock(0); genesis != nil {
+		protocol.Announce(genesis, nil)
+	}
+	protocol.UpdateSubscription()
+	return chain
+}
+
+// Payloads of blocks created by this node are limited, which are
+// usually streamed by 'createblock --file'
+const MaxPayloadSize = 64 * 1024 * 1024
+
+func CreateBlock(chainID string, payload []byte) (*blockchain.Block, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, manageError(InvalidArgument, "payload is larger than %v bytes", MaxPayloadSize)
+	}
+	chain, err := GetChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !chain.IsOwner() {
+		return nil, manageError(PermissionDenied, "chain %v is not owned by this node", chainID)
+	}
+	if err := checkRecovered(chain); err != nil {
+		return nil, err
+	}
+
+	block := chain.CreateBlock(payload)
+	if !chain.SaveBlock(block) {
+		return nil, manageError(Internal, "failed to save block")
+	}
+	protocol.Announce(block, nil)
+	return block, nil
+}
+
+// Maximum blocks generated by one call
+const MaxGenerateCount = 1000
+
+// Create blocks with random payloads quickly, only for regtest
+func GenerateBlocks(chainID string, count int) ([]*blockchain.Block, error) {
+	if !utils.IsRegtest() {
+		return nil, manageError(PermissionDenied, "blocks can only be generated on regtest")
+	}
+	if count <= 0 || count > MaxGenerateCount {
+		return nil, manageError(InvalidArgument, "count must be between 1 and %v", MaxGenerateCount)
+	}
+
+	var blocks []*blockchain.Block
+	for i := 0; i < count; i++ {
+		payload := make([]byte, 32)
+		_, _ = rand.Read(payload)
+		block, err := CreateBlock(chainID, payload)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func AddChain(id string) error {
+	if blockchain.LoadChain(id) != nil {
+		return manageError(AlreadyExists, "chain already added")
+	}
+
+	discovered, err := blockchain.GetDiscoveredChain(id)
+	if err != nil {
+		return manageError(Internal, "failed to get discovered chain: %v", err)
+	}
+	if discovered != nil {
+		protocol.SubscribeChain(discovered.Genesis)
+	} else {
+		blockchain.NewReadonlyChain(id).Sync()
+		protocol.UpdateSubscription()
+	}
+	protocol.RequestChain(id)
+	return nil
+}
+
+// Rebuild an owned chain from mnemonic words, blocks are synced from peers.
+// Another passphrase derives another key, so chain ID is checked if given.
+func RecoverChain(mnemonic string, passphrase string, chainID string) (*blockchain.Chain, error) {
+	key, err := crypto.FromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, manageError(InvalidArgument, "%v", err)
+	}
+	if len(chainID) > 0 && key.ToAddress() != chainID {
+		return nil, manageError(InvalidArgument, "words and passphrase are not of chain %v", chainID)
+	}
+	if chain := blockchain.LoadChain(key.ToAddress()); chain != nil && chain.IsOwner() {
+		return nil, manageError(AlreadyExists, "chain %v is already owned", chain.ID)
+	}
+
+	chain, err := blockchain.RecoverChain(key)
+	if err != nil {
+		return nil, manageError(Internal, "failed to recover chain: %v", err)
+	}
+	protocol.UpdateSubscription()
+	protocol.RequestChain(chain.ID)
+	return chain, nil
+}
+
+// Blocks of a recovering chain are refused until it is synced to
+// the height advertised by peers, otherwise the chain would fork
+func checkRecovered(chain *blockchain.Chain) error {
+	if !chain.IsRecovering() {
+		return nil
+	}
+	best := protocol.SharedScheduler.BestKnown(chain.ID)
+	if best == 0 {
+		return manageError(Unavailable, "chain %v is recovering, no peer has advertised it yet", chain.ID)
+	}
+	if chain.Count < best {
+		return manageError(Unavailable, "chain %v is recovering, %v of %v blocks synced", chain.ID, chain.Count, best)
+	}
+	if err := chain.FinishRecovery(); err != nil {
+		return manageError(Internal, "failed to finish recovery: %v", err)
+	}
+	utils.L.With(utils.Fields{"chain_id": chain.ID}).Infof("chain %v is recovered at height %v", chain.ID, chain.Count)
+	return nil
+}
+
+func DeleteChain(id string) error {
+	chain := blockchain.LoadChain(id)
+	if chain == nil {
+		return manageError(NotFound, "deleting chain is not exist")
+	}
+	blockchain.SharedStorage().CleanChain(chain)
+	blockchain.ResetChainCache()
+	protocol.UpdateSubscription()
+	return nil
+}
+
+// Saved peers with online status, count 0 for all peers
+func GetPeers(count int) []*PeerStatus {
+	var peers []*PeerStatus
+	for _, peer := range network.SharedStorage().GetPeers(count) {
+		if SharedServer != nil {
+			if online := SharedServer.Peer(peer.Addr); online != nil {
+				peers = append(peers, &PeerStatus{online, true})
+				continue
+			}
+		}
+		peers = append(peers, &PeerStatus{peer, false})
+	}
+	return peers
+}
+
+// Peers are saved by websocket URLs as they are dialed
+func peerURL(addr string) (string, error) {
+	if len(addr) == 0 {
+		return "", manageError(InvalidArgument, "'addr' is required")
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", manageError(InvalidArgument, "invalid 'addr': %v", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return "", manageError(InvalidArgument, "'addr' is not a websocket URL")
+	}
+	if len(u.Hostname()) == 0 {
+		return "", manageError(InvalidArgument, "'addr' has no host")
+	}
+	return u.String(), nil
+}
+
+// Address is normalized before saved, the saved one is returned
+func AddPeer(addr string) (string, error) {
+	addr, err := peerURL(addr)
+	if err != nil {
+		return "", err
+	}
+	peer := &network.Peer{Addr: addr, Rank: 100}
+	peer.Save()
+	return addr, nil
+}
+
+func ConnectPeer(addr string) error {
+	if SharedServer == nil {
+		return manageError(Unavailable, "network service is not running")
+	}
+	if peer := SharedServer.Peer(addr); peer != nil {
+		return manageError(AlreadyExists, "already connected")
+	}
+
+	peer := network.SharedStorage().GetPeer(addr)
+	if peer == nil {
+		peer = network.NewPeer(addr, 100)
+	}
+	if err := SharedServer.Connect(peer); err != nil {
+		return manageError(Unavailable, "%v", err)
+	}
+	return nil
+}
+
+func DisconnPeer(addr string) error {
+	if SharedServer == nil {
+		return manageError(Unavailable, "network service is not running")
+	}
+	peer := SharedServer.Peer(addr)
+	if peer == nil {
+		return manageError(NotFound, "peer is not connected")
+	}
+	peer.Close()
+	return nil
+}
+
+func DeletePeer(addr string) error {
+	if SharedServer != nil {
+		if peer := SharedServer.Peer(addr); peer != nil {
+			peer.Close()
+			network.SharedStorage().DeletePeer(peer)
+			return nil
+		}
+	}
+	if peer := network.SharedStorage().GetPeer(addr); peer != nil {
+		network.SharedStorage().DeletePeer(peer)
+		return nil
+	}
+	return manageError(NotFound, "peer is not exist")
+}
+
+func RetryWebhook(id int64) error {
+	delivery := webhook.SharedStorage().GetDelivery(id)
+	if delivery == nil || !delivery.Dead {
+		return manageError(NotFound, "dead delivery is not exist")
+	}
+	webhook.Retry(delivery)
+	return nil
+}
+
+func DeleteWebhook(id int64) error {
+	delivery := webhook.SharedStorage().GetDelivery(id)
+	if delivery == nil || !delivery.Dead {
+		return manageError(NotFound, "dead delivery is not exist")
+	}
+	webhook.SharedStorage().DeleteDelivery(delivery)
+	return nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/utils"
+	"net/http"
+)
+
+// Prometheus metrics at /metrics, served on the port of P2P server
+// when 'metrics.port' is 0. Metrics are not authenticated, so that is
+// refused unless the P2P server only listens on loopback.
+func MetricsService(ctx context.Context) {
+	if !utils.GetBool("metrics.enabled") {
+		return
+	}
+
+	blockchain.AddBlockSavedHook(func(block *blockchain.Block) {
+		metrics.BlocksSaved.Inc(block.ChainID())
+	})
+
+	if utils.GetInt("metrics.port") == 0 {
+		if host := utils.GetString("server.host"); !isLoopback(host) {
+			utils.L.Errorf("metrics service is not started: P2P server listens on %v, "+
+				"'metrics.port' 0 would serve metrics to anyone, set another port", host)
+			return
+		}
+		http.HandleFunc("/metrics", metrics.Handler)
+		utils.L.Infof("metrics service start at /metrics of P2P server")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metrics.Handler)
+
+	addr := fmt.Sprintf("%v:%v", utils.GetString("metrics.host"), utils.GetString("metrics.port"))
+	utils.L.Infof("metrics service start at %v", addr)
+	if err := serveHTTP(ctx, &http.Server{Addr: addr, Handler: mux}); err != nil {
+		utils.L.Fatal(err)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/protocol"
+	"github.com/Infnote/infnotechain/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const Version = "0.2"
+
+var startTime = time.Now()
+
+type ChainStatus struct {
+	ID        string
+	Count     uint64
+	BestKnown uint64
+	Progress  float64
+}
+
+type NodeStatus struct {
+	Version          string
+	Protocol         string
+	Network          string
+	Uptime           time.Duration
+	NodeID           string
+	Config           map[string]string
+	Inbound          int
+	Outbound         int
+	Chains           []*ChainStatus
+	BytesReceived    uint64
+	BytesSent        uint64
+	MessagesReceived map[string]uint64
+	MessagesSent     map[string]uint64
+	DatabaseSize     int64
+	PayloadSize      int64
+}
+
+// Settings worth knowing when checking a running node
+var statusConfigKeys = []string{
+	"server.host",
+	"server.port",
+	"manage.host",
+	"manage.port",
+	"api.enabled",
+	"api.port",
+	"data.file",
+	"data.root",
+	"message.encoding",
+	"discovery.policy",
+	"light.enabled",
+	"peers.auth",
+}
+
+func GetNodeStatus() *NodeStatus {
+	status := &NodeStatus{
+		Version:          Version,
+		Protocol:         protocol.ProtocolVersion,
+		Network:          utils.Network(),
+		Uptime:           time.Since(startTime),
+		NodeID:           network.LocalNodeID(),
+		Config:           map[string]string{},
+		BytesReceived:    metrics.BytesReceived.Value(),
+		BytesSent:        metrics.BytesSent.Value(),
+		MessagesReceived: metrics.MessagesReceived.Values(),
+		MessagesSent:     metrics.MessagesSent.Values(),
+		DatabaseSize:     fileSize(utils.GetString("data.file")),
+		PayloadSize:      payloadSize(utils.GetString("data.root")),
+	}
+
+	for _, key := range statusConfigKeys {
+		status.Config[key] = utils.GetString(key)
+	}
+
+	// peers connected by this node are servers
+	if SharedServer != nil {
+		for _, peer := range SharedServer.Peers() {
+			if peer.IsServer {
+				status.Outbound++
+			} else {
+				status.Inbound++
+			}
+		}
+	}
+
+	for _, chain := range blockchain.LoadAllChains() {
+		best := protocol.SharedScheduler.BestKnown(chain.ID)
+		progress := 1.0
+		if best > chain.Count {
+			progress = float64(chain.Count) / float64(best)
+		}
+		status.Chains = append(status.Chains, &ChainStatus{chain.ID, chain.Count, best, progress})
+	}
+	return status
+}
+
+// Walking the payload directory is slow with many blocks,
+// its size is computed at most once in the interval
+const payloadSizeInterval = time.Minute
+
+var payloadSizeCache struct {
+	sync.Mutex
+	root    string
+	size    int64
+	updated time.Time
+}
+
+func payloadSize(root string) int64 {
+	payloadSizeCache.Lock()
+	defer payloadSizeCache.Unlock()
+	if payloadSizeCache.root != root || time.Since(payloadSizeCache.updated) > payloadSizeInterval {
+		payloadSizeCache.root = root
+		payloadSizeCache.size = fileSize(root)
+		payloadSizeCache.updated = time.Now()
+	}
+	return payloadSizeCache.size
+}
+
+// Size of a file or total size of files in a directory
+func fileSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Applications follow new blocks at /blocks by WebSocket or Server-Sent Events,
+// optional query 'chain' filters a chain and 'from' replays blocks from the height
+// before live blocks. Every event is identified by "[chain id]:[height]",
+// streams are resumed by 'from' or header 'Last-Event-ID' after disconnection.
+type blockSubscriber struct {
+	chainID string
+	blocks  chan *blockchain.Block
+}
+
+type blockHub struct {
+	subscribers map[*blockSubscriber]bool
+	lock        sync.Mutex
+}
+
+type streamEvent struct {
+	ChainID string          `json:"chain_id"`
+	Block   json.RawMessage `json:"block"`
+}
+
+const streamBufferSize = 256
+const streamHistoryBatch = 100
+
+var sharedHub = &blockHub{subscribers: map[*blockSubscriber]bool{}}
+
+var streamUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+func (h *blockHub) subscribe(chainID string) *blockSubscriber {
+	sub := &blockSubscriber{chainID, make(chan *blockchain.Block, streamBufferSize)}
+	h.lock.Lock()
+	h.subscribers[sub] = true
+	h.lock.Unlock()
+	return sub
+}
+
+func (h *blockHub) unsubscribe(sub *blockSubscriber) {
+	h.lock.Lock()
+	if h.subscribers[sub] {
+		delete(h.subscribers, sub)
+		close(sub.blocks)
+	}
+	h.lock.Unlock()
+}
+
+// Subscribers too slow to receive are dropped, they need to resume
+func (h *blockHub) publish(block *blockchain.Block) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for sub := range h.subscribers {
+		if len(sub.chainID) > 0 && sub.chainID != block.ChainID() {
+			continue
+		}
+		select {
+		case sub.blocks <- block:
+		default:
+			utils.L.Warning("block stream subscriber is too slow, dropped")
+			delete(h.subscribers, sub)
+			close(sub.blocks)
+		}
+	}
+}
+
+func eventID(block *blockchain.Block) string {
+	return fmt.Sprintf("%v:%v", block.ChainID(), block.Height)
+}
+
+func eventData(block *blockchain.Block) []byte {
+	data, err := json.Marshal(streamEvent{block.ChainID(), block.Serialize()})
+	if err != nil {
+		utils.L.Fatal(err)
+	}
+	return data
+}
+
+// Heights to replay from for each chain
+func streamCursor(r *http.Request, chainID string) (map[string]uint64, error) {
+	cursor := map[string]uint64{}
+
+	if from := r.URL.Query().Get("from"); len(from) > 0 {
+		height, err := strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'from': %v", err)
+		}
+		cursor = replayFrom(chainID, height)
+	}
+
+	if last := r.Header.Get("Last-Event-ID"); len(last) > 0 {
+		parts := strings.Split(last, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid 'Last-Event-ID'")
+		}
+		height, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'Last-Event-ID': %v", err)
+		}
+		if len(chainID) == 0 || chainID == parts[0] {
+			cursor[parts[0]] = height + 1
+		}
+	}
+
+	return cursor, nil
+}
+
+// Replay the chain, or all chains if chain ID is empty, from the height
+func replayFrom(chainID string, height uint64) map[string]uint64 {
+	cursor := map[string]uint64{}
+	if len(chainID) > 0 {
+		cursor[chainID] = height
+	} else {
+		for _, chain := range blockchain.LoadAllChains() {
+			cursor[chain.ID] = height
+		}
+	}
+	return cursor
+}
+
+// Send blocks saved from now on to the caller until the context is done or
+// sending failed, blocks from the height are replayed first if replay is set
+func WatchBlocks(ctx context.Context, chainID string, replay bool, from uint64, send func(block *blockchain.Block) error) error {
+	if len(chainID) > 0 && blockchain.LoadChain(chainID) == nil {
+		return manageError(NotFound, "chain %v is not exist", chainID)
+	}
+
+	cursor := map[string]uint64{}
+	if replay {
+		cursor = replayFrom(chainID, from)
+	}
+
+	sub := sharedHub.subscribe(chainID)
+	defer sharedHub.unsubscribe(sub)
+
+	// the subscriber is closed when the context is done while waiting
+	// for blocks, the watcher exits with it if streaming stopped first
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			sharedHub.unsubscribe(sub)
+		case <-done:
+		}
+	}()
+
+	if err := streamBlocks(ctx, sub, cursor, send); err != nil {
+		return err
+	}
+	if ctx.Err() == nil {
+		return manageError(Unavailable, "watching is too slow to receive blocks")
+	}
+	return nil
+}
+
+// Replay blocks from cursor then send live blocks,
+// live blocks already replayed are skipped
+func streamBlocks(ctx context.Context, sub *blockSubscriber, cursor map[string]uint64, send func(block *blockchain.Block) error) error {
+	for id, from := range cursor {
+		chain := blockchain.LoadChain(id)
+		if chain == nil {
+			delete(cursor, id)
+			continue
+		}
+		for from < chain.Count {
+			to := from + streamHistoryBatch - 1
+			if to > chain.Count-1 {
+				to = chain.Count - 1
+			}
+			for _, block := range chain.GetBlocksContext(ctx, from, to) {
+				if block.Pruned {
+					return payloadUnavailable(block)
+				}
+				if err := send(block); err != nil {
+					return err
+				}
+			}
+			from = to + 1
+		}
+		cursor[id] = from
+	}
+
+	for block := range sub.blocks {
+		if next, ok := cursor[block.ChainID()]; ok && block.Height < next {
+			continue
+		}
+		if err := send(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleWebSocketStream(w http.ResponseWriter, r *http.Request, sub *blockSubscriber, cursor map[string]uint64) {
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.L.Warningf("%v", err)
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	// stop streaming when client closed
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				sharedHub.unsubscribe(sub)
+				return
+			}
+		}
+	}()
+
+	err = streamBlocks(r.Context(), sub, cursor, func(block *blockchain.Block) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteMessage(websocket.TextMessage, eventData(block))
+	})
+	if err != nil {
+		utils.L.Debugf("block stream closed: %v", err)
+	}
+	_ = conn.WriteMessage(websocket.CloseMessage, []byte{})
+}
+
+func handleEventStream(w http.ResponseWriter, r *http.Request, sub *blockSubscriber, cursor map[string]uint64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	go func() {
+		<-r.Context().Done()
+		sharedHub.unsubscribe(sub)
+	}()
+
+	err := streamBlocks(r.Context(), sub, cursor, func(block *blockchain.Block) error {
+		_, err := fmt.Fprintf(w, "id: %v\nevent: block\ndata: %s\n\n", eventID(block), eventData(block))
+		flusher.Flush()
+		return err
+	})
+	if err != nil {
+		utils.L.Debugf("block stream closed: %v", err)
+	}
+}
+
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	chainID := r.URL.Query().Get("chain")
+	if len(chainID) > 0 && blockchain.LoadChain(chainID) == nil {
+		http.Error(w, "chain not found", http.StatusNotFound)
+		return
+	}
+
+	cursor, err := streamCursor(r, chainID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// subscribe before replaying history so no block is missed
+	sub := sharedHub.subscribe(chainID)
+	defer sharedHub.unsubscribe(sub)
+
+	if websocket.IsWebSocketUpgrade(r) {
+		handleWebSocketStream(w, r, sub, cursor)
+	} else {
+		handleEventStream(w, r, sub, cursor)
+	}
+}
@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/services"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/spf13/viper"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func freePort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return port
+}
+
+// API service on a free local port, returns its base URL
+// and a function stops it and waits until it stopped
+func startAPIService(t *testing.T) (string, func()) {
+	port := freePort(t)
+	host, old := utils.GetString("api.host"), utils.GetString("api.port")
+	utils.Set("api.host", "127.0.0.1")
+	utils.Set("api.port", port)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	go func() {
+		services.APIService(ctx)
+		close(stopped)
+	}()
+	stop := func() {
+		cancel()
+		<-stopped
+	}
+
+	addr := "127.0.0.1:" + port
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		if i > 50 {
+			stop()
+			t.Fatal("api service is not started")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	utils.Set("api.host", host)
+	utils.Set("api.port", old)
+	return "http://" + addr, stop
+}
+
+func TestAPI(t *testing.T) {
+	defer useTempDatabase(t)()
+	base, stop := startAPIService(t)
+	defer stop()
+	base += "/api/v1/"
+	chain := readonlyChain(t, "api")
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"GET", "chains", "", http.StatusOK},
+		{"GET", "chains/" + chain.ID, "", http.StatusOK},
+		{"GET", "chains/unknown", "", http.StatusNotFound},
+		{"GET", "chains/" + chain.ID + "/blocks", "", http.StatusOK},
+		{"GET", "chains/" + chain.ID + "/blocks?limit=0", "", http.StatusBadRequest},
+		{"GET", "chains/" + chain.ID + "/blocks?from=x", "", http.StatusBadRequest},
+		{"GET", "chains/" + chain.ID + "/blocks/0", "", http.StatusNotFound},
+		{"GET", "chains/" + chain.ID + "/blocks/hash?remote=maybe", "", http.StatusBadRequest},
+		{"POST", "chains/" + chain.ID + "/blocks", "{", http.StatusBadRequest},
+		{"PUT", "chains", "", http.StatusMethodNotAllowed},
+		{"POST", "peers", `{"addr": ""}`, http.StatusBadRequest},
+		{"POST", "peers", `{"addr": "http://127.0.0.1:32767"}`, http.StatusBadRequest},
+		{"POST", "peers", `{"addr": "ws://"}`, http.StatusBadRequest},
+		{"POST", "peers", `{"addr": "ws://127.0.0.1:32767"}`, http.StatusCreated},
+		{"GET", "peers?limit=101", "", http.StatusBadRequest},
+		{"GET", "unknown", "", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		request, err := http.NewRequest(c.method, base+c.path, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != c.status {
+			t.Errorf("%v %v: unexpected status %v, expected %v", c.method, c.path, response.StatusCode, c.status)
+		}
+	}
+
+	response, err := http.Get(base + "peers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	peers := &struct {
+		Total int `json:"total"`
+		Peers []struct {
+			Addr string `json:"addr"`
+		} `json:"peers"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(peers); err != nil {
+		t.Fatal(err)
+	}
+	if peers.Total != 1 || peers.Peers[0].Addr != "ws://127.0.0.1:32767" {
+		t.Fatalf("unexpected peers: %+v", peers)
+	}
+}
+
+// Peers are saved with normalized addresses, bodies are limited in size
+func TestAPIRequestBody(t *testing.T) {
+	defer useTempDatabase(t)()
+	base, stop := startAPIService(t)
+	defer stop()
+	base += "/api/v1/"
+
+	response, err := http.Post(base+"peers", "application/json", strings.NewReader(`{"addr": "WS://127.0.0.1:32767"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	added := map[string]string{}
+	if err := json.NewDecoder(response.Body).Decode(&added); err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusCreated || added["addr"] != "ws://127.0.0.1:32767" {
+		t.Fatalf("unexpected response %v: %v", response.StatusCode, added)
+	}
+	if peers := services.GetPeers(0); len(peers) != 1 || peers[0].Addr != added["addr"] {
+		t.Fatalf("peer is not saved with the returned address: %+v", peers)
+	}
+
+	body := fmt.Sprintf(`{"addr": "ws://%v"}`, strings.Repeat("a", 8*1024))
+	response, err = http.Post(base+"peers", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("unexpected status %v of a large body", response.StatusCode)
+	}
+}
+
+func TestAPIAuthorization(t *testing.T) {
+	defer useTempDatabase(t)()
+	base, stop := startAPIService(t)
+	defer stop()
+	base += "/api/v1/"
+
+	viper.Set("manage.tokens", []map[string]string{
+		{"token": "reader", "scope": "read"},
+		{"token": "admin", "scope": "admin"},
+	})
+	defer viper.Set("manage.tokens", nil)
+
+	cases := []struct {
+		method string
+		token  string
+		status int
+	}{
+		{"GET", "", http.StatusUnauthorized},
+		{"GET", "Bearer unknown", http.StatusUnauthorized},
+		{"GET", "Bearer reader", http.StatusOK},
+		{"POST", "Bearer reader", http.StatusForbidden},
+		{"POST", "Bearer admin", http.StatusCreated},
+	}
+
+	for i, c := range cases {
+		body := fmt.Sprintf(`{"addr": "ws://127.0.0.1:%v"}`, 32000+i)
+		request, err := http.NewRequest(c.method, base+"peers", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(c.token) > 0 {
+			request.Header.Set("Authorization", c.token)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != c.status {
+			t.Errorf("%v with %q: unexpected status %v, expected %v", c.method, c.token, response.StatusCode, c.status)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package test
+
+import (
+	"github.com/Infnote/infnotechain/services"
+	"github.com/spf13/viper"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	viper.Set("manage.tokens", []map[string]string{
+		{"token": "reader", "scope": "read"},
+		{"token": "admin", "scope": "admin"},
+	})
+	defer viper.Set("manage.tokens", nil)
+
+	if services.Authorize("Bearer reader", services.ScopeRead) != nil {
+		t.Fail()
+	}
+	if services.Authorize("reader", services.ScopeAdmin) == nil {
+		t.Fail()
+	}
+	if services.Authorize("Bearer admin", services.ScopeAdmin) != nil {
+		t.Fail()
+	}
+	if services.Authorize("", services.ScopeRead) == nil {
+		t.Fail()
+	}
+	if services.Authorize("Bearer unknown", services.ScopeRead) == nil {
+		t.Fail()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/Infnote/infnotechain/network"
 	"github.com/Infnote/infnotechain/protocol"
 	"log"
 	"os"
@@ -36,6 +37,7 @@ func TestBehaviorMap(t *testing.T) {
 }
 
 func TestInfoReaction(t *testing.T) {
+	defer useTempDatabase(t)()
 	info := protocol.Info{
 		Peers: 10,
 		Chains: map[string]uint64{
@@ -65,3 +67,105 @@ func TestRequestBlocks(t *testing.T) {
 		printMessage(msg)
 	}
 }
+
+func TestAnnounceBlockMap(t *testing.T) {
+	announce := &protocol.AnnounceBlock{
+		ChainID: "19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45",
+		Height:  10,
+		Hash:    "DiuvcftK8K51umFQpFY71ipefjxMQ1dRyYsDyNrUozbP",
+	}
+
+	if protocol.MapType(announce) != "announce:block" {
+		t.Fail()
+	}
+
+	if _, ok := protocol.MapBehavior("announce:block").(*protocol.AnnounceBlock); !ok {
+		t.Fail()
+	}
+
+	printMessage(protocol.NewMessage(announce))
+}
+
+func TestAuth(t *testing.T) {
+	peer := network.NewPeer("ws://localhost:32767", 100)
+	info := protocol.NewInfoFor(peer)
+
+	auth := protocol.NewAuth(info.Challenge, info.NodeID)
+	auth.Sender = peer
+	if err := auth.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	auth.Sender = network.NewPeer("ws://localhost:32768", 100)
+	if err := auth.Validate(); err == nil || err.Code != "UnauthorizedError" {
+		t.Fail()
+	}
+
+	printMessage(protocol.NewMessage(auth))
+}
+
+func TestRequestChains(t *testing.T) {
+	if _, ok := protocol.MapBehavior("request:chains").(*protocol.RequestChains); !ok {
+		t.Fail()
+	}
+
+	req := protocol.RequestChains{ChainIDs: []string{"19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45"}}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range req.React() {
+		printMessage(protocol.NewMessage(v))
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	peer := network.NewPeer("ws://localhost:32767", 100)
+	if !protocol.IsSubscribed(peer, "19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45", 10) {
+		t.Fail()
+	}
+
+	sub := &protocol.Subscribe{ChainIDs: []string{"19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45"}, Discovery: true, Sender: peer}
+	if err := sub.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	sub.React()
+
+	if !protocol.IsSubscribed(peer, "19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45", 10) {
+		t.Fail()
+	}
+	if protocol.IsSubscribed(peer, "1A2i1BPdJc1ptZgGMXRjCT4ahXDg4zJWM5", 10) {
+		t.Fail()
+	}
+	if !protocol.IsSubscribed(peer, "1A2i1BPdJc1ptZgGMXRjCT4ahXDg4zJWM5", 0) {
+		t.Fail()
+	}
+}
+
+func TestRequestBlock(t *testing.T) {
+	if _, ok := protocol.MapBehavior("request:block").(*protocol.RequestBlock); !ok {
+		t.Fail()
+	}
+	if err := (protocol.RequestBlock{}).Validate(); err == nil {
+		t.Fail()
+	}
+
+	req := protocol.RequestBlock{Hash: "DiuvcftK8K51umFQpFY71ipefjxMQ1dRyYsDyNrUozbP"}
+	for _, v := range req.React() {
+		printMessage(protocol.NewMessage(v))
+	}
+}
+
+func TestInfoNetwork(t *testing.T) {
+	peer := network.NewPeer("ws://127.0.0.1:52767", 0)
+	info := protocol.Info{Version: protocol.ProtocolVersion, Network: "testnet", Sender: peer}
+	if err := info.Validate(); err == nil || err.Code != "IncompatibleNetworkError" || !peer.Rejected {
+		t.Fail()
+	}
+
+	// peers of earlier versions are mainnet nodes
+	info = protocol.Info{Version: protocol.ProtocolVersion}
+	if err := info.Validate(); err != nil {
+		t.Fail()
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain"
 	"github.com/Infnote/infnotechain/database"
+	"github.com/Infnote/infnotechain/utils"
 	"github.com/kr/pretty"
 	"github.com/mr-tron/base58"
+	"github.com/spf13/viper"
 	"log"
 	"testing"
 )
@@ -13,6 +15,7 @@ import (
 var chain *blockchain.Chain
 
 func init() {
+	utils.LoadConfig("", "", "")
 	database.Register()
 	//chain = blockchain.LoadAllChains()[0]
 }
@@ -63,3 +66,22 @@ func TestBlock(t *testing.T) {
 	fmt.Println(block.ChainID())
 	fmt.Println(block.Validate())
 }
+
+func TestLightRetains(t *testing.T) {
+	viper.Set("light.enabled", true)
+	viper.Set("light.window", 10)
+	viper.Set("light.ranges", []string{"19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45:20-30"})
+	defer viper.Set("light.enabled", false)
+
+	c := blockchain.NewReadonlyChain("19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45")
+	c.Count = 100
+
+	if c.RetainedFrom() != 90 {
+		t.Fail()
+	}
+	for h, retained := range map[uint64]bool{0: true, 1: false, 25: true, 31: false, 89: false, 90: true} {
+		if c.Retains(h) != retained {
+			t.Errorf("height %v should be retained: %v", h, retained)
+		}
+	}
+}
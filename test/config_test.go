@@ -0,0 +1,112 @@
+package test
+
+import (
+	"github.com/Infnote/infnotechain/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ifc-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer utils.LoadConfig("", "", "")
+
+	file := filepath.Join(dir, "config.yaml")
+	write := func(content string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("download:\n    timeout: 7\nhooks:\n    retries: 3\n")
+	utils.LoadConfig(file, "", "")
+	port := utils.GetInt("server.port")
+	defer utils.Set("server.port", port)
+	if utils.GetInt("download.timeout") != 7 {
+		t.Fatalf("config file is not loaded: %v", utils.GetInt("download.timeout"))
+	}
+
+	// readers run during reloading
+	stop := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					utils.GetInt("download.timeout")
+					utils.GetString("log.level")
+				}
+			}
+		}()
+	}
+
+	write("hooks:\n    retries: 5\nserver:\n    port: 1234\n")
+	changes, err := utils.ReloadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := utils.ReloadConfig(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if utils.GetInt("download.timeout") != 30 {
+		t.Fatalf("removed key is not reverted to default: %v", utils.GetInt("download.timeout"))
+	}
+	if utils.GetInt("hooks.retries") != 5 {
+		t.Fatalf("changed key is not applied: %v", utils.GetInt("hooks.retries"))
+	}
+	if utils.GetInt("server.port") != port {
+		t.Fatalf("key needs restart is changed: %v", utils.GetInt("server.port"))
+	}
+	if len(changes.Restart) != 1 || changes.Restart[0] != "server.port" {
+		t.Fatalf("unexpected changes need restart: %v", changes.Restart)
+	}
+
+	write("")
+	if _, err := utils.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if utils.GetInt("hooks.retries") != 8 {
+		t.Fatalf("removed key is not reverted to default: %v", utils.GetInt("hooks.retries"))
+	}
+}
+
+func TestRenamedConfigKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ifc-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer utils.LoadConfig("", "", "")
+	defer utils.Set("api.enabled", utils.GetBool("api.enabled"))
+	defer utils.Set("api.port", utils.GetString("api.port"))
+
+	file := filepath.Join(dir, "config.yaml")
+	content := "stream:\n    enabled: false\n    port: 4000\napi:\n    enabled: true\n"
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	utils.LoadConfig(file, "", "")
+
+	if utils.GetInt("api.port") != 4000 {
+		t.Fatalf("old key is not applied: %v", utils.GetInt("api.port"))
+	}
+	if !utils.GetBool("api.enabled") {
+		t.Fatal("new key in the file should not be replaced by the old key")
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/protocol"
+	"github.com/Infnote/infnotechain/utils"
+	"testing"
+	"time"
+)
+
+// Genesis of a chain created in another database, unknown to this one
+func foreignGenesis(t *testing.T) *blockchain.Block {
+	defer useTempDatabase(t)()
+	chain := blockchain.CreateChainWithKey(crypto.NewKey(), []byte(`{"name": "foreign"}`))
+	genesis := chain.GetLocalBlock(0)
+	if genesis == nil {
+		t.Fatal("genesis is not created")
+	}
+	return genesis
+}
+
+// Ask for the chain as an announcement of it does
+func requestChain(t *testing.T, id string) {
+	behaviors := protocol.AnnounceBlock{ChainID: id, Height: 0, Hash: "hash"}.React()
+	if len(behaviors) != 1 {
+		t.Fatalf("chain %v is not requested", id)
+	}
+	if request, ok := behaviors[0].(*protocol.RequestChains); !ok || request.ChainIDs[0] != id {
+		t.Fatalf("unexpected request: %#v", behaviors[0])
+	}
+}
+
+func respondChain(t *testing.T, genesis *blockchain.Block) {
+	response := &protocol.ResponseChains{Chains: []protocol.ChainGenesis{{Genesis: genesis.Serialize(), Count: 1}}}
+	if err := response.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	response.React()
+}
+
+func isDiscovered(t *testing.T, id string) bool {
+	discovered, err := blockchain.GetDiscoveredChain(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return discovered != nil
+}
+
+// Responses are only accepted once for chains requested
+func TestDiscoveryResponses(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("discovery.policy", utils.GetString("discovery.policy"))
+	genesis := foreignGenesis(t)
+	id := genesis.ChainID()
+
+	utils.Set("discovery.policy", protocol.DiscoveryList)
+	respondChain(t, genesis)
+	if isDiscovered(t, id) {
+		t.Fatal("chain never requested should be dropped")
+	}
+
+	// the response is taken but not kept without discovery
+	requestChain(t, id)
+	utils.Set("discovery.policy", protocol.DiscoveryNone)
+	respondChain(t, genesis)
+	utils.Set("discovery.policy", protocol.DiscoveryList)
+	respondChain(t, genesis)
+	if isDiscovered(t, id) {
+		t.Fatal("chain responded is not forgotten")
+	}
+
+	requestChain(t, id)
+	respondChain(t, genesis)
+	if !isDiscovered(t, id) {
+		t.Fatal("chain requested is not discovered")
+	}
+	select {
+	case announce := <-protocol.AnnounceChannel:
+		if announce.ChainID != id {
+			t.Fatalf("unexpected announcement of chain %v", announce.ChainID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("discovered chain is not announced")
+	}
+
+	// discovered chains are known to others asking for them
+	behaviors := protocol.RequestChains{ChainIDs: []string{id}}.React()
+	if len(behaviors) != 1 {
+		t.Fatal("discovered chain is not responded")
+	}
+	if response := behaviors[0].(*protocol.ResponseChains); len(response.Chains) != 1 {
+		t.Fatalf("unexpected response: %#v", response)
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"bytes"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/protocol"
+	"math/rand"
+	"testing"
+)
+
+func newEncodingMessage(size int) *protocol.Message {
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = byte(rand.Intn(256))
+	}
+
+	b := &protocol.BroadcastBlock{}
+	b.SetBlock(&blockchain.Block{
+		Height:    1,
+		Time:      1546300800,
+		PrevHash:  "DiuvcftK8K51umFQpFY71ipefjxMQ1dRyYsDyNrUozbP",
+		Hash:      "dRN1qDV3uvu55Rk6DWURfNXgrpG6iQXQuS6Xb1VTVBR",
+		Signature: "3pz7sD9QeHLLbb7E6Dzr6CA5EZwarXTm8epSiXLzgddgpJR4n3ALGXsPHccZjZMy2MFmUa7Tki1GN9RtRKnu1KTFC",
+		Payload:   payload,
+	})
+	return protocol.NewMessage(b)
+}
+
+func TestProtobufMessage(t *testing.T) {
+	msg := newEncodingMessage(1024)
+	data := msg.SerializeProtobuf()
+
+	decoded, err := protocol.DeserializeProtobufMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID != msg.ID || decoded.Type != "broadcast:block" {
+		t.Fail()
+	}
+
+	if !bytes.Equal(decoded.SerializeProtobuf(), data) {
+		t.Fail()
+	}
+
+	t.Logf("json: %v bytes, protobuf: %v bytes", len(msg.Serialize()), len(data))
+}
+
+func benchmarkEncode(b *testing.B, size int, encode func(msg *protocol.Message) []byte) {
+	msg := newEncodingMessage(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var data []byte
+	for i := 0; i < b.N; i++ {
+		data = encode(msg)
+	}
+	b.ReportMetric(float64(len(data)), "bytes/msg")
+}
+
+func benchmarkDecode(b *testing.B, size int, data []byte, decode func(data []byte) (*protocol.Message, error)) {
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		msg, err := decode(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		behavior, err := protocol.DeserializeBehavior(msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		// decoding of the block in JSON happens in validation
+		if block := behavior.(*protocol.BroadcastBlock).Block; block != nil {
+			if _, err := blockchain.DeserializeBlock(block); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkJSONEncode1KB(b *testing.B) {
+	benchmarkEncode(b, 1024, func(msg *protocol.Message) []byte { return msg.Serialize() })
+}
+
+func BenchmarkProtobufEncode1KB(b *testing.B) {
+	benchmarkEncode(b, 1024, func(msg *protocol.Message) []byte { return msg.SerializeProtobuf() })
+}
+
+func BenchmarkJSONEncode1MB(b *testing.B) {
+	benchmarkEncode(b, 1024*1024, func(msg *protocol.Message) []byte { return msg.Serialize() })
+}
+
+func BenchmarkProtobufEncode1MB(b *testing.B) {
+	benchmarkEncode(b, 1024*1024, func(msg *protocol.Message) []byte { return msg.SerializeProtobuf() })
+}
+
+func BenchmarkJSONDecode1MB(b *testing.B) {
+	data := newEncodingMessage(1024 * 1024).Serialize()
+	benchmarkDecode(b, 1024*1024, data, protocol.DeserializeMessage)
+}
+
+func BenchmarkProtobufDecode1MB(b *testing.B) {
+	data := newEncodingMessage(1024 * 1024).SerializeProtobuf()
+	benchmarkDecode(b, 1024*1024, data, protocol.DeserializeProtobufMessage)
+}
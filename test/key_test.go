@@ -1,10 +1,12 @@
 package test
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain/crypto"
 	"github.com/mr-tron/base58"
 	"log"
+	"strings"
 	"testing"
 )
 
@@ -82,3 +84,46 @@ func TestSignAndVerify(t *testing.T) {
 
 	fmt.Printf("Signature: %v\n", base58.Encode(sig))
 }
+
+func TestInvalidWIFAndSignature(t *testing.T) {
+	if _, err := crypto.FromWIF("1FuSPXH9MDy2qMpwMNqthGRyLpMpxLZo1r"); err == nil {
+		t.Fatal("address should not be accepted as WIF")
+	}
+	if _, err := crypto.RecoverAddress([]byte{31}, []byte("message")); err == nil {
+		t.Fatal("short signature should not be recovered")
+	}
+	if crypto.Verify("1FuSPXH9MDy2qMpwMNqthGRyLpMpxLZo1r", nil, []byte("message")) {
+		t.Fatal("empty signature should not be verified")
+	}
+}
+
+// Test vector of BIP39 with the master key of BIP32
+func TestMnemonic(t *testing.T) {
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	key, err := crypto.FromMnemonic(words, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(key.Raw()) != "cbedc75b0d6412c85c79bc13875112ef912fd1e756631b5a00330866f22ff184" {
+		t.Fatalf("unexpected key: %x", key.Raw())
+	}
+
+	other, err := crypto.FromMnemonic(words, "")
+	if err != nil || other.ToAddress() == key.ToAddress() {
+		t.Fatal("passphrase should derive another key")
+	}
+
+	if _, err := crypto.FromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ""); err == nil {
+		t.Fatal("mnemonic with invalid checksum should not be accepted")
+	}
+
+	mnemonic, err := crypto.NewMnemonic()
+	if err != nil || len(strings.Fields(mnemonic)) != 24 {
+		t.Fatalf("unexpected mnemonic: %v", mnemonic)
+	}
+	key1, err1 := crypto.FromMnemonic(mnemonic, "secret")
+	key2, err2 := crypto.FromMnemonic(strings.ToUpper("  "+mnemonic+"\n"), "secret")
+	if err1 != nil || err2 != nil || key1.ToWIF() != key2.ToWIF() {
+		t.Fatal("same words should derive the same key")
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/services"
+	"github.com/Infnote/infnotechain/services/command"
+	"github.com/Infnote/infnotechain/utils"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Chains are flushed after services creating blocks stopped,
+// which wait for their handlers before returning
+func TestShutdownOrder(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_, port, _ := net.SplitHostPort(addr)
+	_ = listener.Close()
+
+	defer utils.Set("manage.host", utils.GetString("manage.host"))
+	defer utils.Set("manage.port", utils.GetString("manage.port"))
+	defer utils.Set("daemon.timeout", utils.GetInt("daemon.timeout"))
+	utils.Set("manage.host", "127.0.0.1")
+	utils.Set("manage.port", port)
+	utils.Set("daemon.timeout", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var finished int32
+	services.StartWriter(ctx, command.RunManageServer)
+	services.StartWriter(ctx, func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(200 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+	})
+
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		if i > 50 {
+			t.Fatal("manage service is not started")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	cancel()
+	if !services.WaitWriters(5 * time.Second) {
+		t.Fatal("services creating blocks are not stopped in time")
+	}
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Fatal("returned before a service creating blocks finished")
+	}
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		_ = conn.Close()
+		t.Fatal("manage service is still serving after stopped")
+	}
+}
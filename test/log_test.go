@@ -1,8 +1,77 @@
 package test
 
 import (
+	"encoding/json"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestDump(t *testing.T) {
 }
+
+func TestParseLogLevels(t *testing.T) {
+	def, modules, err := utils.ParseLogLevels("info,network=debug,database=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def != utils.INFO || modules["network"] != utils.DEBUG || modules["database"] != utils.WARNING {
+		t.Fatalf("unexpected levels: %v %v", def, modules)
+	}
+
+	if _, _, err := utils.ParseLogLevels("network=verbose"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestJSONLogRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ifc-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "daemon.log")
+	viper.Set("log.file", file)
+	viper.Set("log.format", "json")
+	viper.Set("log.level", "warning,test=debug")
+	viper.Set("log.maxsize", 1)
+	viper.Set("log.backups", 2)
+	defer func() {
+		viper.Set("log.format", "text")
+		viper.Set("log.level", "info")
+		utils.SetLoggingMode(utils.STDOUT)
+	}()
+	utils.SetLoggingMode(utils.FILE)
+
+	logger := utils.L.With(utils.Fields{"chain_id": "19AZfrNgBh5sxo5eVytX3K3yQvucS5vc45", "height": 1})
+	padding := strings.Repeat("x", 1024)
+	for i := 0; i < 3000; i++ {
+		logger.Debugf("record %v", padding)
+	}
+
+	for _, name := range []string{file, file + ".1", file + ".2"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(file + ".3"); err == nil {
+		t.Fatal("too many backups are kept")
+	}
+
+	data, err := ioutil.ReadFile(file + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.SplitN(string(data), "\n", 2)[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["module"] != "test" || record["level"] != "debug" || record["height"] != float64(1) {
+		t.Fatalf("unexpected record: %v", record)
+	}
+}
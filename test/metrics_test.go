@@ -0,0 +1,35 @@
+package test
+
+import (
+	"bytes"
+	"github.com/Infnote/infnotechain/metrics"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec(t *testing.T) {
+	counter := metrics.NewCounterVec("test_messages_total", "Messages for test.", "type")
+	counter.Inc("info")
+	counter.Add("info", 2)
+	counter.Inc("request:blocks")
+
+	values := counter.Values()
+	if values["info"] != 3 || values["request:blocks"] != 1 {
+		t.Fail()
+	}
+}
+
+func TestMetricsWrite(t *testing.T) {
+	metrics.MessagesReceived.Inc("info")
+	metrics.StorageLatency.Observe("test_write", 0.002)
+
+	buffer := &bytes.Buffer{}
+	metrics.Write(buffer)
+	text := buffer.String()
+	if !strings.Contains(text, `ifc_messages_received_total{type="info"}`) {
+		t.Fail()
+	}
+	if !strings.Contains(text, `ifc_storage_duration_seconds_bucket{operation="test_write",le="0.0025"} 1`) {
+		t.Fail()
+	}
+}
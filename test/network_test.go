@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestMessage(t *testing.T) {
@@ -60,3 +61,28 @@ func TestURLParse(t *testing.T) {
 
 	fmt.Println(result)
 }
+
+// Writes never block on a stuck peer or panic on a closed one
+func TestPeerWrite(t *testing.T) {
+	peer := network.NewPeer("ws://127.0.0.1:32767", 100)
+	go func() { <-peer.Send }()
+	if !peer.Write([]byte("taken"), time.Second) {
+		t.Fatal("message is not taken by the writing loop")
+	}
+	if peer.Write([]byte("stuck"), 10*time.Millisecond) {
+		t.Fatal("message should not be taken without the writing loop")
+	}
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			peer.Write([]byte("racing"), time.Millisecond)
+		}
+		close(done)
+	}()
+	peer.Close()
+	<-done
+	if peer.Write([]byte("closed"), time.Second) || !peer.IsClosed() {
+		t.Fatal("message should not be written to a closed peer")
+	}
+}
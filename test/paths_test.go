@@ -0,0 +1,22 @@
+package test
+
+import (
+	"github.com/Infnote/infnotechain/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHomePaths(t *testing.T) {
+	defer os.Unsetenv("IFC_HOME")
+
+	if err := os.Setenv("IFC_HOME", "/tmp/ifc-home"); err != nil {
+		t.Fatal(err)
+	}
+	if utils.DataDir() != "/tmp/ifc-home" || utils.ConfigDir() != "/tmp/ifc-home" {
+		t.Fatalf("IFC_HOME is not used: %v, %v", utils.DataDir(), utils.ConfigDir())
+	}
+	if utils.ConfigFile() != filepath.Join("/tmp/ifc-home", "config.yaml") {
+		t.Fatalf("unexpected config file: %v", utils.ConfigFile())
+	}
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/services"
+	"testing"
+)
+
+func TestRecoveringChain(t *testing.T) {
+	defer useTempDatabase(t)()
+
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	chain, err := services.RecoverChain(words, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chain.IsOwner() || !chain.IsRecovering() {
+		t.Fatal("recovered chain should be owned and recovering")
+	}
+
+	// no peer has advertised the chain
+	_, err = services.CreateBlock(chain.ID, []byte("fork"))
+	if e, ok := err.(*services.ManageError); !ok || e.Kind != services.Unavailable {
+		t.Fatalf("block should be refused on a recovering chain: %v", err)
+	}
+	if chain.Count != 0 {
+		t.Fatalf("block is created on a recovering chain: %v", chain.Count)
+	}
+
+	// state is kept after the chain is reloaded
+	blockchain.ResetChainCache()
+	if loaded := blockchain.LoadChain(chain.ID); loaded == nil || !loaded.IsRecovering() {
+		t.Fatal("recovering state is not saved")
+	}
+
+	if _, err := services.RecoverChain(words, "", ""); err == nil {
+		t.Fatal("owned chain should not be recovered again")
+	}
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/Infnote/infnotechain/network"
+	"github.com/Infnote/infnotechain/protocol"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/mr-tron/base58"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newTestPeer(addr string) *network.Peer {
+	return &network.Peer{Addr: addr, Send: make(chan []byte, 64)}
+}
+
+// Windows of blocks requested from the peer so far
+func requestedWindows(t *testing.T, peer *network.Peer) [][2]uint64 {
+	var windows [][2]uint64
+	for {
+		select {
+		case data := <-peer.Send:
+			msg, err := protocol.DeserializeMessage(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			request := &protocol.RequestBlocks{}
+			if err := json.Unmarshal(msg.Data, request); err != nil {
+				t.Fatal(err)
+			}
+			windows = append(windows, [2]uint64{request.From, request.To})
+		default:
+			sort.Slice(windows, func(i, j int) bool { return windows[i][0] < windows[j][0] })
+			return windows
+		}
+	}
+}
+
+func readonlyChain(t *testing.T, id string) *blockchain.Chain {
+	chain := blockchain.NewReadonlyChain(id)
+	if err := blockchain.SharedStorage().SaveChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+func TestSchedulerWindows(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("download.window", utils.GetInt("download.window"))
+	defer utils.Set("download.parallel", utils.GetInt("download.parallel"))
+
+	cases := []struct {
+		name     string
+		window   int
+		parallel int
+		counts   []uint64
+		expected [][][2]uint64
+	}{
+		{"one window", 10, 2, []uint64{5}, [][][2]uint64{{{0, 4}}}},
+		{"limited by parallel", 10, 2, []uint64{35}, [][][2]uint64{{{0, 9}, {10, 19}}}},
+		{"split over peers", 10, 2, []uint64{35, 35}, [][][2]uint64{{{0, 9}, {10, 19}}, {{20, 29}, {30, 34}}}},
+		{"short peer", 10, 1, []uint64{35, 8}, [][][2]uint64{{{0, 9}}, nil}},
+	}
+
+	for i, c := range cases {
+		utils.Set("download.window", c.window)
+		utils.Set("download.parallel", c.parallel)
+		chain := readonlyChain(t, fmt.Sprintf("windows-%v", i))
+		scheduler := protocol.NewDownloadScheduler()
+
+		var peers []*network.Peer
+		for j, count := range c.counts {
+			peer := newTestPeer(fmt.Sprintf("peer-%v", j))
+			peers = append(peers, peer)
+			scheduler.Advertise(peer, chain.ID, count)
+		}
+		for j, peer := range peers {
+			if windows := requestedWindows(t, peer); !reflect.DeepEqual(windows, c.expected[j]) {
+				t.Errorf("%v: peer %v requested %v, expected %v", c.name, j, windows, c.expected[j])
+			}
+		}
+	}
+}
+
+func TestSchedulerTimeout(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("download.window", utils.GetInt("download.window"))
+	defer utils.Set("download.timeout", utils.GetInt("download.timeout"))
+	utils.Set("download.window", 10)
+	utils.Set("download.timeout", 1)
+
+	chain := readonlyChain(t, "timeout")
+	scheduler := protocol.NewDownloadScheduler()
+	slow, other := newTestPeer("slow"), newTestPeer("other")
+
+	scheduler.Advertise(slow, chain.ID, 10)
+	scheduler.Advertise(other, chain.ID, 10)
+	if windows := requestedWindows(t, slow); !reflect.DeepEqual(windows, [][2]uint64{{0, 9}}) {
+		t.Fatalf("unexpected windows of the first peer: %v", windows)
+	}
+	if !scheduler.Expects(slow, chain.ID, 5) || scheduler.Expects(other, chain.ID, 5) {
+		t.Fatal("blocks should be expected only from the assigned peer")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+
+	select {
+	case <-other.Send:
+	case <-time.After(5 * time.Second):
+		t.Fatal("window is not reassigned after timeout")
+	}
+	if scheduler.Expects(slow, chain.ID, 5) || !scheduler.Expects(other, chain.ID, 5) {
+		t.Fatal("late response of the timeout peer should be ignored")
+	}
+}
+
+func TestSchedulerCommitInOrder(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("download.window", utils.GetInt("download.window"))
+	utils.Set("download.window", 1)
+
+	key, err := crypto.FromWIF("KxUxDz8wbQbnxmnKiPUX9uquHB5tkPc8tF5U3uxmmb3yqnYf7MZb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks []*blockchain.Block
+	for h := uint64(0); h < 2; h++ {
+		block := &blockchain.Block{Height: h, Time: h, Payload: []byte(fmt.Sprintf("block %v", h))}
+		if h > 0 {
+			block.PrevHash = blocks[h-1].Hash
+		}
+		block.Hash = base58.Encode(utils.SHA256(block.DataForHashing()))
+		block.Signature = base58.Encode(key.Sign(block.DataForHashing()))
+		blocks = append(blocks, block)
+	}
+
+	chain := readonlyChain(t, key.ToAddress())
+	scheduler := protocol.NewDownloadScheduler()
+	peer := newTestPeer("peer")
+	scheduler.Advertise(peer, chain.ID, 2)
+
+	if tips := scheduler.Receive(newTestPeer("stranger"), blocks); len(tips) != 0 {
+		t.Fatal("blocks from a peer not assigned should be ignored")
+	}
+	if tips := scheduler.Receive(peer, blocks[1:]); len(tips) != 0 {
+		t.Fatal("block should not be committed before its previous block")
+	}
+	tips := scheduler.Receive(peer, blocks[:1])
+	if len(tips) != 1 || tips[0].Height != 1 {
+		t.Fatalf("blocks are not committed in order: %v", tips)
+	}
+	if loaded := blockchain.LoadChain(chain.ID); loaded.Count != 2 {
+		t.Fatalf("unexpected count of the chain: %v", loaded.Count)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"github.com/Infnote/infnotechain/blockchain"
 	"github.com/Infnote/infnotechain/database"
+	"github.com/Infnote/infnotechain/utils"
 	"github.com/mr-tron/base58"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -13,6 +17,7 @@ var storage = blockchain.SharedStorage()
 var sqlchain = blockchain.NewOwnedChain("KxUxDz8wbQbnxmnKiPUX9uquHB5tkPc8tF5U3uxmmb3yqnYf7MZb")
 
 func init() {
+	utils.LoadConfig("", "", "")
 	database.Register()
 }
 
@@ -52,3 +57,30 @@ func TestSQLGetBlock(t *testing.T) {
 func TestGetBlocks(t *testing.T) {
 	log.Println(storage.GetBlocks(1, 0, 0))
 }
+
+func TestFindBlock(t *testing.T) {
+	log.Println(storage.FindBlock("DiuvcftK8K51umFQpFY71ipefjxMQ1dRyYsDyNrUozbP"))
+}
+
+// Register a new database in a temporary directory, the shared
+// database is registered again by the returned function
+func useTempDatabase(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "ifc-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := utils.GetString("data.file")
+	utils.Set("data.file", filepath.Join(dir, "data.db"))
+	database.Migrate()
+	database.Register()
+	blockchain.ResetChainCache()
+
+	return func() {
+		database.Close()
+		utils.Set("data.file", file)
+		database.Register()
+		blockchain.ResetChainCache()
+		_ = os.RemoveAll(dir)
+	}
+}
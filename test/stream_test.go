@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/blockchain/crypto"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Owned chain with blocks of heights from 0 to count-1, it is not
+// the chain loaded by services so blocks can be saved while streaming
+func chainWithBlocks(t *testing.T, count int) *blockchain.Chain {
+	key, err := crypto.FromWIF("KxUxDz8wbQbnxmnKiPUX9uquHB5tkPc8tF5U3uxmmb3yqnYf7MZb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := blockchain.CreateChainWithKey(key, []byte("block 0"))
+	for i := 1; i < count; i++ {
+		if !chain.SaveBlock(chain.CreateBlock([]byte(fmt.Sprintf("block %v", i)))) {
+			t.Fatalf("failed to save block %v", i)
+		}
+	}
+	return chain
+}
+
+// Event IDs of Server-Sent Events read from the response
+func readEventIDs(response *http.Response) chan string {
+	ids := make(chan string, 16)
+	go func() {
+		defer close(ids)
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "id: ") {
+				ids <- strings.TrimPrefix(line, "id: ")
+			}
+		}
+	}()
+	return ids
+}
+
+// Event IDs of blocks received by WebSocket
+func readMessageIDs(t *testing.T, conn *websocket.Conn) chan string {
+	ids := make(chan string, 16)
+	go func() {
+		defer close(ids)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			event := &struct {
+				ChainID string `json:"chain_id"`
+				Block   struct {
+					Height uint64 `json:"height"`
+				} `json:"block"`
+			}{}
+			if err := json.Unmarshal(data, event); err != nil {
+				t.Error(err)
+				return
+			}
+			ids <- fmt.Sprintf("%v:%v", event.ChainID, event.Block.Height)
+		}
+	}()
+	return ids
+}
+
+func receive(t *testing.T, ids chan string, count int) []string {
+	var received []string
+	for len(received) < count {
+		select {
+		case id, ok := <-ids:
+			if !ok {
+				t.Fatalf("stream ended after %v", received)
+			}
+			received = append(received, id)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event received after %v", received)
+		}
+	}
+	return received
+}
+
+func TestEventStreamResume(t *testing.T) {
+	defer useTempDatabase(t)()
+	chain := chainWithBlocks(t, 3)
+	base, stop := startAPIService(t)
+	defer stop()
+
+	cases := []struct {
+		name     string
+		query    string
+		lastID   string
+		expected []string
+	}{
+		{"from", "&from=1", "", []string{chain.ID + ":1", chain.ID + ":2"}},
+		{"from the start", "&from=0", "", []string{chain.ID + ":0", chain.ID + ":1", chain.ID + ":2"}},
+		{"last event", "", chain.ID + ":0", []string{chain.ID + ":1", chain.ID + ":2"}},
+		{"last event overrides from", "&from=0", chain.ID + ":1", []string{chain.ID + ":2"}},
+	}
+
+	for _, c := range cases {
+		request, err := http.NewRequest("GET", fmt.Sprintf("%v/blocks?chain=%v%v", base, chain.ID, c.query), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(c.lastID) > 0 {
+			request.Header.Set("Last-Event-ID", c.lastID)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("%v: unexpected content type %q", c.name, response.Header.Get("Content-Type"))
+		}
+
+		ids := readEventIDs(response)
+		if replayed := receive(t, ids, len(c.expected)); !reflect.DeepEqual(replayed, c.expected) {
+			t.Errorf("%v: replayed %v, expected %v", c.name, replayed, c.expected)
+		}
+		_ = response.Body.Close()
+	}
+
+	// blocks saved after replaying are streamed once
+	request, _ := http.NewRequest("GET", fmt.Sprintf("%v/blocks?chain=%v", base, chain.ID), nil)
+	request.Header.Set("Last-Event-ID", chain.ID+":1")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	ids := readEventIDs(response)
+	receive(t, ids, 1)
+	chain.SaveBlock(chain.CreateBlock([]byte("block 3")))
+	if live := receive(t, ids, 1); live[0] != chain.ID+":3" {
+		t.Fatalf("unexpected live event: %v", live)
+	}
+}
+
+func TestWebSocketStream(t *testing.T) {
+	defer useTempDatabase(t)()
+	chain := chainWithBlocks(t, 2)
+	base, stop := startAPIService(t)
+	defer stop()
+
+	url := fmt.Sprintf("ws%v/blocks?chain=%v&from=0", strings.TrimPrefix(base, "http"), chain.ID)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ids := readMessageIDs(t, conn)
+	if replayed := receive(t, ids, 2); !reflect.DeepEqual(replayed, []string{chain.ID + ":0", chain.ID + ":1"}) {
+		t.Fatalf("unexpected replayed blocks: %v", replayed)
+	}
+	chain.SaveBlock(chain.CreateBlock([]byte("block 2")))
+	if live := receive(t, ids, 1); live[0] != chain.ID+":2" {
+		t.Fatalf("unexpected live block: %v", live)
+	}
+}
+
+func TestStreamRequests(t *testing.T) {
+	defer useTempDatabase(t)()
+	chain := readonlyChain(t, "stream")
+	base, stop := startAPIService(t)
+	defer stop()
+
+	cases := []struct {
+		query  string
+		lastID string
+		status int
+	}{
+		{"?chain=unknown", "", http.StatusNotFound},
+		{"?chain=" + chain.ID + "&from=x", "", http.StatusBadRequest},
+		{"?chain=" + chain.ID, "invalid", http.StatusBadRequest},
+		{"?chain=" + chain.ID, chain.ID + ":x", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		request, err := http.NewRequest("GET", base+"/blocks"+c.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(c.lastID) > 0 {
+			request.Header.Set("Last-Event-ID", c.lastID)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != c.status {
+			t.Errorf("%q with %q: unexpected status %v, expected %v", c.query, c.lastID, response.StatusCode, c.status)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"github.com/Infnote/infnotechain/utils"
+	"github.com/Infnote/infnotechain/webhook"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookSign(t *testing.T) {
+	signature := webhook.Sign("secret", "1546300800", []byte(`{"height":0}`))
+	if signature != "cd05903077a33523e5c147ffdac3a040ad5e095295540de460c34879f26756b8" {
+		t.Fail()
+	}
+}
+
+// Run the webhook service until the returned function is called
+func runWebhooks() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	go func() {
+		webhook.Run(ctx)
+		close(stopped)
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+func queueDelivery(url string, height uint64) *webhook.Delivery {
+	delivery := &webhook.Delivery{URL: url, ChainID: "chain", Height: height, Body: []byte(`{}`), Next: time.Now()}
+	webhook.SharedStorage().SaveDelivery(delivery)
+	return delivery
+}
+
+func TestWebhookRetries(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("hooks.block", utils.GetString("hooks.block"))
+	defer utils.Set("hooks.retries", utils.GetInt("hooks.retries"))
+	defer utils.Set("hooks.backoff", utils.GetInt("hooks.backoff"))
+	utils.Set("hooks.retries", 3)
+	utils.Set("hooks.backoff", 1)
+
+	var lock sync.Mutex
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		attempts = append(attempts, time.Now())
+		lock.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	utils.Set("hooks.block", server.URL)
+
+	delivery := queueDelivery(server.URL, 1)
+	stop := runWebhooks()
+	for i := 0; ; i++ {
+		if d := webhook.SharedStorage().GetDelivery(delivery.ID); d.Dead {
+			delivery = d
+			break
+		}
+		if i > 100 {
+			stop()
+			t.Fatal("delivery is not dead after retries")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(attempts) != 3 || delivery.Attempts != 3 || len(delivery.Error) == 0 {
+		t.Fatalf("unexpected attempts: %v requests, %v counted, error %q", len(attempts), delivery.Attempts, delivery.Error)
+	}
+	// backoff doubles after each failure, times are stored in seconds
+	if gap := attempts[2].Sub(attempts[1]); gap < time.Second {
+		t.Fatalf("retried after %v, expected 2s backoff", gap)
+	}
+	if next := delivery.Next.Unix() - attempts[1].Unix(); next < 2 || next > 3 {
+		t.Fatalf("retry scheduled %vs after the second attempt, expected 2s backoff", next)
+	}
+	dead := webhook.SharedStorage().GetDeadDeliveries(0)
+	if len(dead) != 1 || dead[0].ID != delivery.ID {
+		t.Fatalf("delivery is not in dead letters: %v", dead)
+	}
+}
+
+func TestWebhookSlowEndpoint(t *testing.T) {
+	defer useTempDatabase(t)()
+	defer utils.Set("hooks.endpoints", nil)
+
+	release := make(chan bool)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	delivered := make(chan bool, 2)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- true
+	}))
+	defer fast.Close()
+	utils.Set("hooks.endpoints", []map[string]interface{}{{"url": slow.URL}, {"url": fast.URL}})
+
+	queueDelivery(slow.URL, 1)
+	queueDelivery(fast.URL, 1)
+	queueDelivery(fast.URL, 2)
+	stop := runWebhooks()
+	defer stop()
+	defer close(release)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("deliveries are held by a slow endpoint")
+		}
+	}
+}
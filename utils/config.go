@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"github.com/spf13/viper"
+	"sync"
+)
+
+// Viper is not safe for concurrent use, settings are read and changed by
+// these functions since config is reloaded while services are running
+var configLock sync.RWMutex
+
+func Get(key string) interface{} {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.Get(key)
+}
+
+func GetString(key string) string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.GetString(key)
+}
+
+func GetInt(key string) int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.GetInt(key)
+}
+
+func GetInt64(key string) int64 {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.GetInt64(key)
+}
+
+func GetBool(key string) bool {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.GetBool(key)
+}
+
+func GetStringSlice(key string) []string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.GetStringSlice(key)
+}
+
+func UnmarshalKey(key string, rawVal interface{}) error {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return viper.UnmarshalKey(key, rawVal)
+}
+
+// Override a setting, such as '--debug' of 'run'
+func Set(key string, value interface{}) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	viper.Set(key, value)
+}
@@ -1,73 +1,274 @@
 package utils
 
 import (
-	"github.com/op/go-logging"
-	"github.com/spf13/viper"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
 	NONE   int = 0
 	STDOUT int = 1
 	FILE   int = 1 << 1
+	STDERR int = 1 << 2
 )
 
-var level = map[string]logging.Level{
-	"debug":    logging.DEBUG,
-	"info":     logging.INFO,
-	"notice":   logging.NOTICE,
-	"warning":  logging.WARNING,
-	"error":    logging.ERROR,
-	"critical": logging.CRITICAL,
+type Level int
+
+const (
+	CRITICAL Level = iota
+	ERROR
+	WARNING
+	NOTICE
+	INFO
+	DEBUG
+)
+
+var level = map[string]Level{
+	"debug":    DEBUG,
+	"info":     INFO,
+	"notice":   NOTICE,
+	"warning":  WARNING,
+	"error":    ERROR,
+	"critical": CRITICAL,
 }
 
-var L = logging.MustGetLogger("ifc")
-var stdout logging.LeveledBackend
-var fout logging.LeveledBackend
+// Context of a record, common keys are 'peer', 'chain_id', 'height', 'msg_type' and 'msg_id'
+type Fields map[string]interface{}
 
-func init() {
-	formatter := logging.MustStringFormatter(
-		`%{color}[%{time:15:04:05.000}][%{shortfunc}][%{level:.4s}]%{color:reset} %{message}`)
-	stdout = logging.AddModuleLevel(
-		logging.NewBackendFormatter(
-			logging.NewLogBackend(os.Stdout, "", 0), formatter))
-	stdout.SetLevel(logging.INFO, "")
+// Logger writes records to stdout and 'log.file', the module of a record
+// is the package it is logged from, such as 'network' or 'database'
+type Logger struct {
+	fields Fields
+}
 
-	logging.SetBackend(stdout)
+// Settings of running loggers, changed by SetLoggingMode and ApplyLogLevel
+type logSettings struct {
+	sync.Mutex
+	mode    int
+	json    bool
+	level   Level
+	modules map[string]Level
+	file    *rotatingFile
 }
 
-func SetLoggingMode(mode int) {
-	if mode == NONE {
-		logging.SetBackend()
+var L = &Logger{}
+var settings = &logSettings{mode: STDOUT, level: INFO}
+
+// Logger with fields added to every record
+func (l *Logger) With(fields Fields) *Logger {
+	merged := Fields{}
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{fields: merged}
+}
+
+func (l *Logger) Debug(args ...interface{}) {
+	l.log(DEBUG, nil, args...)
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(DEBUG, &format, args...)
+}
+
+func (l *Logger) Info(args ...interface{}) {
+	l.log(INFO, nil, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(INFO, &format, args...)
+}
+
+func (l *Logger) Notice(args ...interface{}) {
+	l.log(NOTICE, nil, args...)
+}
+
+func (l *Logger) Noticef(format string, args ...interface{}) {
+	l.log(NOTICE, &format, args...)
+}
+
+func (l *Logger) Warning(args ...interface{}) {
+	l.log(WARNING, nil, args...)
+}
+
+func (l *Logger) Warningf(format string, args ...interface{}) {
+	l.log(WARNING, &format, args...)
+}
+
+func (l *Logger) Error(args ...interface{}) {
+	l.log(ERROR, nil, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(ERROR, &format, args...)
+}
+
+func (l *Logger) Critical(args ...interface{}) {
+	l.log(CRITICAL, nil, args...)
+}
+
+func (l *Logger) Criticalf(format string, args ...interface{}) {
+	l.log(CRITICAL, &format, args...)
+}
+
+func (l *Logger) Fatal(args ...interface{}) {
+	l.log(CRITICAL, nil, args...)
+	os.Exit(1)
+}
+
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(CRITICAL, &format, args...)
+	os.Exit(1)
+}
+
+// Only called by the methods above, the caller of them is 3 frames up
+func (l *Logger) log(lvl Level, format *string, args ...interface{}) {
+	settings.Lock()
+	defer settings.Unlock()
+
+	if settings.mode == NONE || lvl > settings.maxLevel() {
+		return
+	}
+
+	module, function := caller(3)
+	if lvl > settings.levelOf(module) {
+		return
 	}
 
-	l, ok := level[viper.GetString("log.level")]
-	if !ok {
-		log.Fatalf("log level '%v' is not supported\n", viper.GetString("log.level"))
+	var message string
+	if format != nil {
+		message = fmt.Sprintf(*format, args...)
+	} else {
+		// spaces are always added between arguments
+		message = strings.TrimSuffix(fmt.Sprintln(args...), "\n")
 	}
 
-	file, err := os.OpenFile(viper.GetString("log.file"), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	r := &record{time.Now(), lvl, module, function, message, l.fields}
+	if settings.mode&STDOUT > 0 {
+		if settings.json {
+			_, _ = os.Stdout.Write(r.json())
+		} else {
+			_, _ = os.Stdout.Write(r.text(true))
+		}
+	}
+	if settings.mode&STDERR > 0 {
+		if settings.json {
+			_, _ = os.Stderr.Write(r.json())
+		} else {
+			_, _ = os.Stderr.Write(r.text(true))
+		}
+	}
+	if settings.mode&FILE > 0 && settings.file != nil {
+		if settings.json {
+			_, _ = settings.file.Write(r.json())
+		} else {
+			_, _ = settings.file.Write(r.text(false))
+		}
+	}
+}
+
+// Most verbose level of all modules, records above it are
+// dropped without looking up the caller
+func (s *logSettings) maxLevel() Level {
+	max := s.level
+	for _, l := range s.modules {
+		if l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+func (s *logSettings) levelOf(module string) Level {
+	if l, ok := s.modules[module]; ok {
+		return l
+	}
+	return s.level
+}
+
+// Parse levels like "info,network=debug,database=warning",
+// the entry without a module is the level of all other modules
+func ParseLogLevels(spec string) (Level, map[string]Level, error) {
+	def := INFO
+	modules := map[string]Level{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+
+		module, name := "", entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			module, name = strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+			if len(module) == 0 {
+				return def, nil, fmt.Errorf("log level '%v' has no module", entry)
+			}
+		}
+
+		l, ok := level[name]
+		if !ok && name == "warn" {
+			l, ok = WARNING, true
+		}
+		if !ok {
+			return def, nil, fmt.Errorf("log level '%v' is not supported", name)
+		}
+
+		if len(module) == 0 {
+			def = l
+		} else {
+			modules[module] = l
+		}
+	}
+	return def, modules, nil
+}
+
+// Change levels, format and rotation of running loggers to 'log.*'
+func ApplyLogLevel() error {
+	def, modules, err := ParseLogLevels(GetString("log.level"))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	fileFormatter := logging.MustStringFormatter(
-		`[%{time:2006-01-02 15:04:05.000}][%{longfunc}][%{level:.4s}] %{message}`)
-	fout = logging.AddModuleLevel(
-		logging.NewBackendFormatter(
-			logging.NewLogBackend(file, "", 0), fileFormatter))
+	format := GetString("log.format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("log format '%v' is not supported", format)
+	}
+
+	settings.Lock()
+	defer settings.Unlock()
 
-	stdout.SetLevel(l, "")
-	fout.SetLevel(l, "")
+	settings.level = def
+	settings.modules = modules
+	settings.json = format == "json"
+	if settings.file != nil {
+		settings.file.maxsize = GetInt64("log.maxsize") * 1024 * 1024
+		settings.file.backups = GetInt("log.backups")
+	}
+	return nil
+}
 
-	var backends []logging.Backend
-	if mode&STDOUT > 0 {
-		backends = append(backends, stdout)
+func SetLoggingMode(mode int) {
+	if mode&FILE > 0 {
+		file, err := openRotatingFile(GetString("log.file"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		settings.Lock()
+		settings.file = file
+		settings.Unlock()
 	}
 
-	if mode&FILE == FILE {
-		backends = append(backends, fout)
+	if err := ApplyLogLevel(); err != nil {
+		log.Fatal(err)
 	}
 
-	logging.SetBackend(backends...)
+	settings.Lock()
+	settings.mode = mode
+	settings.Unlock()
 }
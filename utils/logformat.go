@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+var levelNames = []string{"CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+var levelColors = []string{"\033[35m", "\033[31m", "\033[33m", "\033[32m", "", "\033[36m"}
+
+const colorReset = "\033[0m"
+
+type record struct {
+	time     time.Time
+	level    Level
+	module   string
+	function string
+	message  string
+	fields   Fields
+}
+
+// Module and function of the caller skip frames up, the module is the last
+// element of the package path, such as 'network' or 'command'
+func caller(skip int) (string, string) {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "?", "?"
+	}
+	name := runtime.FuncForPC(pc).Name()
+
+	// github.com/Infnote/infnotechain/network.(*Peer).read
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return name[slash+1:], name[slash+1:]
+	}
+	return name[slash+1 : slash+1+dot], name[slash+1+dot+1:]
+}
+
+func shortFunction(function string) string {
+	return function[strings.LastIndex(function, ".")+1:]
+}
+
+// Same as the message formats before JSON format is added, fields are appended as key=value
+func (r *record) text(stdout bool) []byte {
+	var buffer bytes.Buffer
+	name := levelNames[r.level][:4]
+	if stdout {
+		fmt.Fprintf(&buffer, "%v[%v][%v][%v]%v %v",
+			levelColors[r.level], r.time.Format("15:04:05.000"), shortFunction(r.function), name, colorReset, r.message)
+	} else {
+		fmt.Fprintf(&buffer, "[%v][%v][%v] %v",
+			r.time.Format("2006-01-02 15:04:05.000"), r.function, name, r.message)
+	}
+	for _, key := range r.keys() {
+		fmt.Fprintf(&buffer, " %v=%v", key, r.fields[key])
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes()
+}
+
+// One JSON object per line, fields are at the top level
+func (r *record) json() []byte {
+	object := map[string]interface{}{}
+	for k, v := range r.fields {
+		object[k] = v
+	}
+	object["time"] = r.time.Format("2006-01-02T15:04:05.000Z07:00")
+	object["level"] = strings.ToLower(levelNames[r.level])
+	object["module"] = r.module
+	object["func"] = r.function
+	object["msg"] = r.message
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{
+			"time":  object["time"],
+			"level": object["level"],
+			"msg":   fmt.Sprintf("%v (fields not encodable: %v)", r.message, err),
+		})
+	}
+	return append(data, '\n')
+}
+
+func (r *record) keys() []string {
+	var keys []string
+	for k := range r.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
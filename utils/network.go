@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"path/filepath"
+)
+
+const (
+	Mainnet = "mainnet"
+	Testnet = "testnet"
+	Regtest = "regtest"
+)
+
+// Default ports of a network, so nodes of different networks can run on one host
+type networkParams struct {
+	ServerPort  int
+	ManagePort  int
+	APIPort     int
+	MetricsPort int
+}
+
+var networks = map[string]networkParams{
+	Mainnet: {32767, 32700, 32701, 32702},
+	Testnet: {42767, 42700, 42701, 42702},
+	Regtest: {52767, 52700, 52701, 52702},
+}
+
+// Set by '--network'
+var network string
+
+// Network the node belongs to, peers of other networks are rejected
+func Network() string {
+	if len(network) == 0 {
+		return Mainnet
+	}
+	return network
+}
+
+func IsRegtest() bool {
+	return Network() == Regtest
+}
+
+func setNetwork(name string) error {
+	if len(name) == 0 {
+		name = Mainnet
+	}
+	if _, ok := networks[name]; !ok {
+		return fmt.Errorf("network '%v' is not supported, use mainnet, testnet or regtest", name)
+	}
+	network = name
+	return nil
+}
+
+// Data of networks other than mainnet are kept in a sub directory
+func networkDir(dir string) string {
+	if Network() == Mainnet {
+		return dir
+	}
+	return filepath.Join(dir, Network())
+}
+
+func setNetworkDefaults() {
+	params := networks[Network()]
+	viper.SetDefault("server.port", params.ServerPort)
+	viper.SetDefault("manage.port", params.ManagePort)
+	viper.SetDefault("api.port", params.APIPort)
+	viper.SetDefault("metrics.port", params.MetricsPort)
+
+	// regtest nodes only talk to local nodes and act quickly for integration tests
+	if IsRegtest() {
+		viper.SetDefault("server.host", "127.0.0.1")
+		viper.SetDefault("download.timeout", 5)
+		viper.SetDefault("hooks.backoff", 1)
+		viper.SetDefault("daemon.timeout", 3)
+	}
+}
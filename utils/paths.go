@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Set by '--config' and '--datadir'
+var configFile string
+var dataDir string
+
+// Directory of data, logs and the pid file, which is '--datadir' if set, then 'IFC_HOME',
+// /usr/local/var/infnote for root and the XDG data directory for other users.
+// Networks other than mainnet use a sub directory named after the network.
+func DataDir() string {
+	return networkDir(baseDataDir())
+}
+
+// Directory of config.yaml, config is kept with data if '--datadir' or 'IFC_HOME' is set
+func ConfigDir() string {
+	return networkDir(baseConfigDir())
+}
+
+func baseDataDir() string {
+	if len(dataDir) > 0 {
+		return dataDir
+	}
+	if home := os.Getenv("IFC_HOME"); len(home) > 0 {
+		return home
+	}
+	if os.Geteuid() == 0 {
+		return "/usr/local/var/infnote"
+	}
+	return filepath.Join(xdgDir("XDG_DATA_HOME", ".local/share"), "infnote")
+}
+
+func baseConfigDir() string {
+	if len(dataDir) > 0 {
+		return dataDir
+	}
+	if home := os.Getenv("IFC_HOME"); len(home) > 0 {
+		return home
+	}
+	if os.Geteuid() == 0 {
+		return "/usr/local/etc/infnote"
+	}
+	return filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "infnote")
+}
+
+func ConfigFile() string {
+	if len(configFile) > 0 {
+		return configFile
+	}
+	return filepath.Join(ConfigDir(), "config.yaml")
+}
+
+func xdgDir(env string, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(os.Getenv("HOME"), fallback)
+}
+
+func absPath(path string) string {
+	if len(path) == 0 {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
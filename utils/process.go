@@ -1,15 +1,26 @@
 package utils
 
 import (
-	"github.com/spf13/viper"
 	"io/ioutil"
 	"os"
 	"strconv"
 	"syscall"
 )
 
+// Remove pid file if it is written for this process
+func RemovePIDFile() {
+	filename := GetString("daemon.pid")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	if string(content) == strconv.Itoa(os.Getpid()) {
+		_ = os.Remove(filename)
+	}
+}
+
 func CheckProcessAlive() bool {
-	filename := viper.GetString("daemon.pid")
+	filename := GetString("daemon.pid")
 	content, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return false
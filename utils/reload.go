@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"sort"
+	"strings"
+)
+
+// Settings read only when services start, changes of them are reported
+// and take effect after restart. Others are read every time they are used.
+var restartKeys = []string{
+	"server.",
+	"manage.host",
+	"manage.port",
+	"manage.tls.",
+	"api.",
+	"metrics.",
+	"data.",
+	"daemon.",
+	"node.key",
+	"log.file",
+	"light.enabled",
+}
+
+type ConfigChanges struct {
+	Applied []string
+	Restart []string
+}
+
+func needsRestart(key string) bool {
+	for _, prefix := range restartKeys {
+		if key == prefix || (strings.HasSuffix(prefix, ".") && strings.HasPrefix(key, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read config file again and apply changed settings which do not need
+// restart, running values of the others are kept. Settings removed from
+// the file are reverted to defaults.
+func ReloadConfig() (*ConfigChanges, error) {
+	fresh := viper.New()
+	setConfigPaths(fresh)
+	if err := fresh.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	if fresh.IsSet("log.level") {
+		if _, _, err := ParseLogLevels(fresh.GetString("log.level")); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := replaceConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyLogLevel(); err != nil {
+		return nil, err
+	}
+
+	L.Infof("config reloaded, applied: %v, restart needed: %v", changes.Applied, changes.Restart)
+	return changes, nil
+}
+
+// Config of the running instance is replaced as a whole, so defaults are
+// kept for keys not in the file, then keys need restart are set back
+func replaceConfig() (*ConfigChanges, error) {
+	configLock.Lock()
+	defer configLock.Unlock()
+
+	running := map[string]interface{}{}
+	for _, key := range viper.AllKeys() {
+		running[key] = viper.Get(key)
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	applyRenamedKeys()
+
+	keys := viper.AllKeys()
+	for key := range running {
+		if !viper.IsSet(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := &ConfigChanges{}
+	for _, key := range keys {
+		value, old := viper.Get(key), running[key]
+		if fmt.Sprintf("%v", value) == fmt.Sprintf("%v", old) {
+			continue
+		}
+		if needsRestart(key) {
+			viper.Set(key, old)
+			changes.Restart = append(changes.Restart, key)
+			continue
+		}
+		changes.Applied = append(changes.Applied, key)
+	}
+	return changes, nil
+}
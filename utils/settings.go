@@ -1,73 +1,204 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
+// Only defaults are set when imported, paths are set and created by LoadConfig
 func init() {
-	if err := os.MkdirAll("/usr/local/var/infnote/payloads/", 0755); err != nil {
-		L.Fatal(err)
-	}
-
 	viper.SetDefault("server.host", "0.0.0.0")
-	viper.SetDefault("server.port", 32767)
 	viper.SetDefault("manage.host", "127.0.0.1")
-	viper.SetDefault("manage.port", 32700)
-	viper.SetDefault("data.file", "/usr/local/var/infnote/data.db")
-	viper.SetDefault("data.root", "/usr/local/var/infnote/payloads/")
+	viper.SetDefault("manage.tls.cert", "")
+	viper.SetDefault("manage.tls.key", "")
+	viper.SetDefault("manage.tls.ca", "")
+	viper.SetDefault("manage.client.tls", false)
+	viper.SetDefault("manage.client.servername", "")
+	viper.SetDefault("manage.client.ca", "")
+	viper.SetDefault("manage.client.cert", "")
+	viper.SetDefault("manage.client.key", "")
+	viper.SetDefault("manage.client.token", "")
+	viper.SetDefault("api.enabled", true)
+	viper.SetDefault("api.host", "127.0.0.1")
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.host", "127.0.0.1")
 	viper.SetDefault("peers.sync", false)
 	viper.SetDefault("peers.retry", 5)
+	viper.SetDefault("peers.auth", true)
 	viper.SetDefault("hooks.block", nil)
-	viper.SetDefault("daemon.pid", "/tmp/ifc.pid")
+	viper.SetDefault("hooks.retries", 8)
+	viper.SetDefault("hooks.backoff", 2)
+	viper.SetDefault("hooks.timeout", 10)
+	viper.SetDefault("daemon.timeout", 10)
 	viper.SetDefault("message.division", true)
 	viper.SetDefault("message.maxsize", 1)
+	viper.SetDefault("message.encoding", "protobuf")
+	viper.SetDefault("download.window", 64)
+	viper.SetDefault("download.parallel", 2)
+	viper.SetDefault("download.timeout", 30)
+	viper.SetDefault("discovery.policy", "list")
+	viper.SetDefault("light.enabled", false)
+	viper.SetDefault("light.window", 1000)
+	viper.SetDefault("light.ranges", []string{})
 
 	// debug, info, notice, warning, error, critical
 	viper.SetDefault("log.level", "info")
-	viper.SetDefault("log.file", "/usr/local/var/infnote/daemon.log")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.maxsize", 100)
+	viper.SetDefault("log.backups", 5)
+}
+
+// Read config file and create directories of data, file, dir and net are
+// '--config', '--datadir' and '--network' which fall back to defaults when empty
+func LoadConfig(file string, dir string, net string) {
+	if err := setNetwork(net); err != nil {
+		L.Fatal(err)
+	}
+	configFile = absPath(file)
+	dataDir = absPath(dir)
+	setNetworkDefaults()
+
+	home := DataDir()
+	viper.SetDefault("data.file", filepath.Join(home, "data.db"))
+	viper.SetDefault("data.root", filepath.Join(home, "payloads")+"/")
+	viper.SetDefault("node.key", filepath.Join(home, "node.key"))
+	viper.SetDefault("log.file", filepath.Join(home, "daemon.log"))
+	viper.SetDefault("daemon.pid", filepath.Join(home, "ifc.pid"))
 
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("/usr/local/etc/infnote/")
+	configLock.Lock()
+	setConfigPaths(viper.GetViper())
 	err := viper.ReadInConfig()
+	if err == nil {
+		applyRenamedKeys()
+	}
+	configLock.Unlock()
 	if err != nil {
 		L.Infof("%v", err)
 		L.Infof("failed to load config file, using default settings")
 	}
+
+	dirs := []string{
+		GetString("data.root"),
+		filepath.Dir(GetString("data.file")),
+		filepath.Dir(GetString("node.key")),
+		filepath.Dir(GetString("log.file")),
+		filepath.Dir(GetString("daemon.pid")),
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			L.Fatal(err)
+		}
+	}
+}
+
+// Keys renamed in newer versions, old names in config files still work
+var renamedKeys = map[string]string{
+	"stream.enabled": "api.enabled",
+	"stream.host":    "api.host",
+	"stream.port":    "api.port",
+}
+
+// Values of old names in the config file are set to new names which are
+// not in the file, configLock must be held
+func applyRenamedKeys() {
+	file := viper.New()
+	setConfigPaths(file)
+	if file.ReadInConfig() != nil {
+		return
+	}
+	for old, key := range renamedKeys {
+		if file.IsSet(old) && !file.IsSet(key) {
+			L.Warningf("setting '%v' is renamed to '%v'", old, key)
+			viper.Set(key, file.Get(old))
+		}
+	}
+}
+
+func setConfigPaths(v *viper.Viper) {
+	v.SetConfigFile(ConfigFile())
+	v.SetConfigType("yaml")
 }
 
 func Migrate() {
-	err := os.MkdirAll("/usr/local/etc/infnote/", 0755)
+	file := ConfigFile()
+	err := os.MkdirAll(filepath.Dir(file), 0755)
 	if err != nil {
 		L.Fatal(err)
 	}
 
-	err = ioutil.WriteFile("/usr/local/etc/infnote/config.yaml", []byte(
+	// addresses and timeouts are defaults of the network
+	err = ioutil.WriteFile(file, []byte(fmt.Sprintf(
 `daemon:
     # ifc service process
-    pid: /tmp/ifc.pid
+    pid: %[1]v/ifc.pid
+    # seconds to wait for peers, streams and webhooks when stopping
+    timeout: %[7]v
 data:
     # all chains and blocks are saved here
-    file: /usr/local/var/infnote/data.db
-    root: /usr/local/var/infnote/payloads/
+    file: %[1]v/data.db
+    root: %[1]v/payloads/
 log:
     # avaliable: debug, info, notice, warning, error, critical
+    # levels of modules are set like: info,network=debug,database=warning
     level: info
-    file: /usr/local/var/infnote/daemon.log
+    # avaliable: text, json
+    # json writes one object per line with module, peer, chain_id, height, msg_type and msg_id
+    format: text
+    file: %[1]v/daemon.log
+    # log file is rotated when it is larger than maxsize (MB), 0 disables rotation
+    maxsize: 100
+    # rotated files kept as daemon.log.1, daemon.log.2...
+    backups: 5
 manage:
     # rpc management listen on
     host: 127.0.0.1
-    port: 32700
+    port: %[3]v
+    # tokens for rpc management and api, no authorization when empty
+    # avaliable scope: read, admin
+    # tokens:
+    #     - token: change-me
+    #       scope: admin
+    # TLS is enabled when cert and key are set,
+    # client certificates signed by ca are required when ca is set
+    tls:
+        cert: ""
+        key: ""
+        ca: ""
+    # credentials used by 'ifc cli'
+    client:
+        tls: false
+        servername: ""
+        ca: ""
+        cert: ""
+        key: ""
+        token: ""
+api:
+    # HTTP JSON API at /api/v1 and block stream at /blocks for applications
+    enabled: true
+    host: 127.0.0.1
+    port: %[4]v
+metrics:
+    # Prometheus metrics at /metrics, port 0 serves on the port of ifc service
+    # without authentication, which is refused unless server.host is loopback
+    enabled: true
+    host: 127.0.0.1
+    port: %[5]v
 peers:
     retry: 5
     # ifc will automatically sync peer list with any connected peer when set true
     sync: false
+    # blocks are only exchanged with peers proved their node identity
+    auth: true
+node:
+    # identity of this node, created at first run
+    key: %[1]v/node.key
 server:
     # ifc service listen on
-    host: 0.0.0.0
-    port: 32767
+    host: %[6]v
+    port: %[2]v
 message:
     # message for transmit blocks will be divided to several messages
     division: true
@@ -75,13 +206,58 @@ message:
     # max block payload size (MB) of one message can contain
     # only effective when division is true
     maxsize: 1
-# hooks:
-    # ifc service will call this by POST every time when receive a new block
-    # blocks: http://localhost/hooks/new_block`), 0655)
+
+    # avaliable: json, protobuf
+    # protobuf is used only with peers supporting it, otherwise json
+    encoding: protobuf
+download:
+    # missing blocks are requested from all peers by windows of this many blocks
+    window: 64
+    # max windows requested from one peer at the same time
+    parallel: 2
+    # seconds to wait before requesting a window from another peer
+    timeout: %[8]v
+discovery:
+    # what to do with chains learned from peers
+    # avaliable: none (ignore), list (list as available), auto (subscribe)
+    policy: list
+light:
+    # light node keeps all blocks but only payloads of recent blocks,
+    # older payloads are fetched from other peers when requested
+    enabled: false
+    # keep payloads of this many recent blocks of every chain
+    window: 1000
+    # also keep payloads of these heights, format: [chain id]:[from]-[to]
+    ranges: []
+hooks:
+    # ifc service will POST every new block to these endpoints, each endpoint
+    # is delivered in order of blocks saved, independent of other endpoints
+    # endpoints:
+    #     - url: http://localhost/hooks/new_block
+    #       # header 'X-Infnote-Signature' is HMAC-SHA256 of "[X-Infnote-Timestamp].[body]"
+    #       secret: change-me
+    #       # only blocks of these chains are posted, all chains if empty
+    #       chains: []
+
+    # failed deliveries are retried after backoff seconds doubled every time,
+    # then listed by 'deadhooks' in cli
+    retries: 8
+    backoff: %[9]v
+    # seconds to wait for the response of an endpoint
+    timeout: 10`,
+		DataDir(),
+		GetInt("server.port"),
+		GetInt("manage.port"),
+		GetInt("api.port"),
+		GetInt("metrics.port"),
+		GetString("server.host"),
+		GetInt("daemon.timeout"),
+		GetInt("download.timeout"),
+		GetInt("hooks.backoff"))), 0655)
 
 	if err != nil {
 		L.Fatal(err)
 	}
 
-	L.Info("create config file at /usr/local/etc/infnote/config.yaml")
+	L.Infof("create config file at %v", file)
 }
@@ -0,0 +1,23 @@
+package webhook
+
+import "time"
+
+type Storage interface {
+	SaveDelivery(delivery *Delivery)
+	UpdateDelivery(delivery *Delivery)
+	DeleteDelivery(delivery *Delivery)
+	GetDelivery(id int64) *Delivery
+	GetDueEndpoints(now time.Time) []string
+	GetDueDeliveries(url string, now time.Time, count int) []*Delivery
+	GetDeadDeliveries(count int) []*Delivery
+}
+
+var instance Storage
+
+func RegisterStorage(s Storage) {
+	instance = s
+}
+
+func SharedStorage() Storage {
+	return instance
+}
@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/Infnote/infnotechain/blockchain"
+	"github.com/Infnote/infnotechain/metrics"
+	"github.com/Infnote/infnotechain/utils"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Every saved block is queued for each endpoint interested in its chain,
+// deliveries failed are retried with exponential backoff and moved to
+// dead letters after 'hooks.retries' attempts.
+type Endpoint struct {
+	URL    string   `mapstructure:"url"`
+	Secret string   `mapstructure:"secret"`
+	Chains []string `mapstructure:"chains"`
+}
+
+type Delivery struct {
+	ID       int64
+	URL      string
+	ChainID  string
+	Height   uint64
+	Body     []byte
+	Attempts int
+	Next     time.Time
+	Error    string
+	Dead     bool
+}
+
+const maxBackoff = time.Hour
+const dispatchBatch = 32
+
+var wake = make(chan bool, 1)
+
+// Endpoints in 'hooks.endpoints' and the single 'hooks.block'
+func Endpoints() []Endpoint {
+	var endpoints []Endpoint
+	if err := utils.UnmarshalKey("hooks.endpoints", &endpoints); err != nil {
+		utils.L.Warningf("invalid hooks.endpoints: %v", err)
+	}
+	if hook := utils.GetString("hooks.block"); len(hook) > 0 {
+		endpoints = append(endpoints, Endpoint{URL: hook})
+	}
+	return endpoints
+}
+
+func endpoint(url string) *Endpoint {
+	for _, e := range Endpoints() {
+		if e.URL == url {
+			return &e
+		}
+	}
+	return nil
+}
+
+func (e Endpoint) accepts(chainID string) bool {
+	if len(e.Chains) == 0 {
+		return true
+	}
+	for _, id := range e.Chains {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// HMAC-SHA256 of "[timestamp].[body]" in hex
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Queue deliveries of a saved block
+func Enqueue(block *blockchain.Block) {
+	chainID := block.ChainID()
+	queued := false
+	for _, e := range Endpoints() {
+		if !e.accepts(chainID) {
+			continue
+		}
+		SharedStorage().SaveDelivery(&Delivery{
+			URL:     e.URL,
+			ChainID: chainID,
+			Height:  block.Height,
+			Body:    block.Serialize(),
+			Next:    time.Now(),
+		})
+		queued = true
+	}
+
+	if queued {
+		select {
+		case wake <- true:
+		default:
+		}
+	}
+}
+
+// Put a dead delivery back to queue
+func Retry(delivery *Delivery) {
+	delivery.Dead = false
+	delivery.Attempts = 0
+	delivery.Next = time.Now()
+	SharedStorage().UpdateDelivery(delivery)
+
+	select {
+	case wake <- true:
+	default:
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	d := time.Duration(utils.GetInt("hooks.backoff")) * time.Second
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+func post(delivery *Delivery) error {
+	e := endpoint(delivery.URL)
+	if e == nil {
+		return fmt.Errorf("endpoint removed from config")
+	}
+
+	request, err := http.NewRequest("POST", delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Infnote-Delivery", strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set("X-Infnote-Chain-ID", delivery.ChainID)
+	request.Header.Set("X-Infnote-Timestamp", timestamp)
+	if len(e.Secret) > 0 {
+		request.Header.Set("X-Infnote-Signature", "sha256="+Sign(e.Secret, timestamp, delivery.Body))
+	}
+
+	client := &http.Client{Timeout: time.Duration(utils.GetInt("hooks.timeout")) * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %v", response.Status)
+	}
+	return nil
+}
+
+func deliver(delivery *Delivery) {
+	logger := utils.L.With(utils.Fields{"chain_id": delivery.ChainID, "height": delivery.Height})
+	err := post(delivery)
+	if err == nil {
+		logger.Debugf("webhook delivered: %v %v:%v", delivery.URL, delivery.ChainID, delivery.Height)
+		metrics.WebhookDeliveries.Inc("success")
+		SharedStorage().DeleteDelivery(delivery)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.Error = err.Error()
+	if delivery.Attempts >= utils.GetInt("hooks.retries") {
+		delivery.Dead = true
+		logger.Warningf("webhook delivery %v is dead: %v", delivery.ID, err)
+		metrics.WebhookDeliveries.Inc("dead")
+	} else {
+		delivery.Next = time.Now().Add(backoff(delivery.Attempts))
+		logger.Debugf("webhook delivery %v failed, retry at %v: %v", delivery.ID, delivery.Next, err)
+		metrics.WebhookDeliveries.Inc("retry")
+	}
+	SharedStorage().UpdateDelivery(delivery)
+}
+
+// Deliver due blocks of an endpoint in order of queuing until none is due
+func deliverEndpoint(ctx context.Context, url string) {
+	for {
+		deliveries := SharedStorage().GetDueDeliveries(url, time.Now(), dispatchBatch)
+		if len(deliveries) == 0 {
+			return
+		}
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return
+			}
+			deliver(delivery)
+		}
+	}
+}
+
+// Endpoints are delivered concurrently, one goroutine each, so a slow
+// endpoint only delays its own deliveries. Failed deliveries do not hold
+// later ones, receivers should rely on heights for ordering.
+// Stopped when the context is done, deliveries in flight are finished
+// and the rest are left in storage for next start
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	var lock sync.Mutex
+	busy := map[string]bool{}
+
+	for {
+		for _, url := range SharedStorage().GetDueEndpoints(time.Now()) {
+			lock.Lock()
+			running := busy[url]
+			busy[url] = true
+			lock.Unlock()
+			if running {
+				continue
+			}
+
+			wg.Add(1)
+			go func(url string) {
+				defer wg.Done()
+				deliverEndpoint(ctx, url)
+				lock.Lock()
+				delete(busy, url)
+				lock.Unlock()
+			}(url)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Queue saved blocks and deliver them until the context is done
+func Serve(ctx context.Context) {
+	blockchain.AddBlockSavedHook(Enqueue)
+	Run(ctx)
+}